@@ -2,6 +2,7 @@ package api
 
 import (
 	"host-monitor-agent/cache"
+	"host-monitor-agent/models"
 	"net/http"
 	"time"
 
@@ -34,10 +35,70 @@ func (h *Handler) GetMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, metrics)
 }
 
+// GetEvents 获取最近的事件
+func (h *Handler) GetEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"events": h.metricsCache.GetEvents(),
+	})
+}
+
+// GetHostileIPs 获取暴力破解检测判定的恶意来源
+func (h *Handler) GetHostileIPs(c *gin.Context) {
+	metrics := h.metricsCache.Get()
+
+	if metrics == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "metrics not ready",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics.Security.BruteForce)
+}
+
+// GetCompliance 获取合规基线检查结果
+func (h *Handler) GetCompliance(c *gin.Context) {
+	metrics := h.metricsCache.Get()
+
+	if metrics == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "metrics not ready",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, metrics.Compliance)
+}
+
+// GetPackages 获取已安装软件包清单，name 参数按名称精确过滤
+func (h *Handler) GetPackages(c *gin.Context) {
+	metrics := h.metricsCache.Get()
+
+	if metrics == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "metrics not ready",
+		})
+		return
+	}
+
+	packages := metrics.Packages
+	if name := c.Query("name"); name != "" {
+		matched := []models.InstalledPackage{}
+		for _, pkg := range packages.Packages {
+			if pkg.Name == name {
+				matched = append(matched, pkg)
+			}
+		}
+		packages.Packages = matched
+	}
+
+	c.JSON(http.StatusOK, packages)
+}
+
 // HealthCheck 健康检查
 func (h *Handler) HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "ok",
 		"time":   time.Now(),
 	})
-}
\ No newline at end of file
+}
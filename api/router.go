@@ -20,5 +20,17 @@ func SetupRouter(metricsCache *cache.MetricsCache) *gin.Engine {
 	// 获取监控指标
 	router.GET("/metrics", handler.GetMetrics)
 
+	// 获取最近事件
+	router.GET("/events", handler.GetEvents)
+
+	// 获取暴力破解检测的恶意来源
+	router.GET("/security/hostile", handler.GetHostileIPs)
+
+	// 获取合规基线检查结果
+	router.GET("/compliance", handler.GetCompliance)
+
+	// 获取已安装软件包清单
+	router.GET("/packages", handler.GetPackages)
+
 	return router
-}
\ No newline at end of file
+}
@@ -0,0 +1,307 @@
+package benchmark
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 检查结果状态
+const (
+	StatusPass  = "pass"
+	StatusFail  = "fail"
+	StatusError = "error" // 无法完成检查（如无权限），不计入得分
+)
+
+// maxContentSize file_content 读取的文件大小上限
+const maxContentSize = 1 << 20
+
+// 系统文件路径
+const (
+	procSysDir      = "/proc/sys"
+	procModulesFile = "/proc/modules"
+	mountsFile      = "/proc/self/mounts"
+	sysvRCDirs      = "/etc/rc[2-5].d"
+)
+
+// systemdDirs systemd 单元目录，按优先级从高到低；/etc 与 /run 中指向 /dev/null 的单元表示被 mask，
+// 发行版随软件包提供的静态启用链接（如 getty.target.wants）位于 /usr/lib 与 /lib
+var systemdDirs = []string{"/etc/systemd/system", "/run/systemd/system", "/usr/lib/systemd/system", "/lib/systemd/system"}
+
+// modprobeDirs modprobe 配置目录
+var modprobeDirs = []string{"/etc/modprobe.d", "/run/modprobe.d", "/usr/lib/modprobe.d", "/lib/modprobe.d"}
+
+// Result 单条规则的检查结果
+type Result struct {
+	Status string
+	Detail string // 实际值或失败原因
+}
+
+// Evaluate 执行规则
+func (r *Rule) Evaluate() Result {
+	switch r.Type {
+	case TypeFileContent:
+		return r.checkFileContent()
+	case TypeFilePermission:
+		return r.checkFilePermission()
+	case TypeSysctl:
+		return r.checkSysctl()
+	case TypeKernelModule:
+		return r.checkKernelModule()
+	case TypeService:
+		return r.checkService()
+	case TypeMountOption:
+		return r.checkMountOption()
+	}
+	return Result{Status: StatusError, Detail: "unknown type " + r.Type}
+}
+
+// passIf 根据条件返回通过或失败
+func passIf(ok bool, detail string) Result {
+	if ok {
+		return Result{Status: StatusPass, Detail: detail}
+	}
+	return Result{Status: StatusFail, Detail: detail}
+}
+
+// checkFileContent 文件不存在时 no_match 视为通过
+func (r *Rule) checkFileContent() Result {
+	file, err := os.Open(r.Path)
+	if os.IsNotExist(err) {
+		return passIf(r.Expect == "no_match", "file not found")
+	}
+	if err != nil {
+		return Result{Status: StatusError, Detail: err.Error()}
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxContentSize))
+	if err != nil {
+		return Result{Status: StatusError, Detail: err.Error()}
+	}
+
+	match := r.pattern.Find(data)
+	if r.Expect == "no_match" {
+		if match != nil {
+			return Result{Status: StatusFail, Detail: "found: " + strings.TrimSpace(string(match))}
+		}
+		return Result{Status: StatusPass, Detail: "no match"}
+	}
+	if match == nil {
+		return Result{Status: StatusFail, Detail: "no match"}
+	}
+	return Result{Status: StatusPass, Detail: "found: " + strings.TrimSpace(string(match))}
+}
+
+// checkFilePermission 权限不得超过 mode，属主属组按名称或数字比较
+func (r *Rule) checkFilePermission() Result {
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		return Result{Status: StatusError, Detail: err.Error()}
+	}
+
+	var problems []string
+	if r.Mode != "" && unixMode(info.Mode())&^r.mode != 0 {
+		problems = append(problems, fmt.Sprintf("mode %04o exceeds %04o", unixMode(info.Mode()), r.mode))
+	}
+
+	owner, group := fileOwner(info)
+	if r.Owner != "" && r.Owner != owner {
+		problems = append(problems, fmt.Sprintf("owner %s, expected %s", owner, r.Owner))
+	}
+	if r.Group != "" && r.Group != group {
+		problems = append(problems, fmt.Sprintf("group %s, expected %s", group, r.Group))
+	}
+
+	if len(problems) > 0 {
+		return Result{Status: StatusFail, Detail: strings.Join(problems, "; ")}
+	}
+	return Result{Status: StatusPass, Detail: fmt.Sprintf("%04o %s:%s", unixMode(info.Mode()), owner, group)}
+}
+
+// unixMode 转换为传统 Unix 权限位（含 setuid/setgid/sticky）
+func unixMode(mode os.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 01000
+	}
+	return bits
+}
+
+// checkSysctl 比较时把连续空白视为一个空格（如 ip_local_port_range）
+func (r *Rule) checkSysctl() Result {
+	path := filepath.Join(procSysDir, strings.ReplaceAll(r.Key, ".", "/"))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Result{Status: StatusError, Detail: err.Error()}
+	}
+	actual := strings.Join(strings.Fields(string(data)), " ")
+	expected := strings.Join(strings.Fields(r.Value), " ")
+	return passIf(actual == expected, actual)
+}
+
+// checkKernelModule blacklisted 要求模块未加载且被 blacklist 或 install 到 /bin/true、/bin/false
+func (r *Rule) checkKernelModule() Result {
+	loaded, err := moduleLoaded(r.Module)
+	if err != nil {
+		return Result{Status: StatusError, Detail: err.Error()}
+	}
+
+	switch r.State {
+	case "loaded":
+		return passIf(loaded, loadedDetail(loaded))
+	case "not_loaded":
+		return passIf(!loaded, loadedDetail(loaded))
+	}
+
+	disabled := moduleDisabled(r.Module)
+	detail := loadedDetail(loaded)
+	if disabled {
+		detail += ", blacklisted"
+	} else {
+		detail += ", not blacklisted"
+	}
+	return passIf(!loaded && disabled, detail)
+}
+
+// loadedDetail 模块加载状态描述
+func loadedDetail(loaded bool) string {
+	if loaded {
+		return "loaded"
+	}
+	return "not loaded"
+}
+
+// normalizeModule 模块名中 - 与 _ 等价
+func normalizeModule(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// moduleLoaded 检查 /proc/modules
+func moduleLoaded(module string) (bool, error) {
+	file, err := os.Open(procModulesFile)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	module = normalizeModule(module)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 && fields[0] == module {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// moduleDisabled 检查 modprobe.d 中的 blacklist 或 install <module> /bin/true|/bin/false
+func moduleDisabled(module string) bool {
+	module = normalizeModule(module)
+	for _, dir := range modprobeDirs {
+		files, _ := filepath.Glob(filepath.Join(dir, "*.conf"))
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			for _, line := range strings.Split(string(data), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) < 2 || normalizeModule(fields[1]) != module {
+					continue
+				}
+				switch fields[0] {
+				case "blacklist":
+					return true
+				case "install":
+					if len(fields) > 2 && (strings.HasSuffix(fields[2], "/true") || strings.HasSuffix(fields[2], "/false")) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// checkService 通过 systemd 的 .wants/.requires 链接或 SysV rc 链接判断是否开机启用
+// 指向 /dev/null 的单元视为被 mask，即禁用
+func (r *Rule) checkService() Result {
+	enabled := serviceEnabled(r.Service)
+	detail := "disabled"
+	if enabled {
+		detail = "enabled"
+	}
+	return passIf(enabled == (r.State == "enabled"), detail)
+}
+
+// serviceEnabled 检查服务是否开机启用
+func serviceEnabled(service string) bool {
+	unit := service
+	if !strings.Contains(unit, ".") {
+		unit += ".service"
+	}
+	for _, dir := range systemdDirs[:2] {
+		if target, err := os.Readlink(filepath.Join(dir, unit)); err == nil && target == "/dev/null" {
+			return false
+		}
+	}
+	for _, dir := range systemdDirs {
+		for _, pattern := range []string{"*.wants", "*.requires"} {
+			if matches, _ := filepath.Glob(filepath.Join(dir, pattern, unit)); len(matches) > 0 {
+				return true
+			}
+		}
+	}
+	name := strings.TrimSuffix(unit, ".service")
+	if matches, _ := filepath.Glob(filepath.Join(sysvRCDirs, "S[0-9][0-9]"+name)); len(matches) > 0 {
+		return true
+	}
+	return false
+}
+
+// checkMountOption 挂载点必须是独立挂载且包含全部选项，同一挂载点以最后一次挂载为准
+func (r *Rule) checkMountOption() Result {
+	data, err := os.ReadFile(mountsFile)
+	if err != nil {
+		return Result{Status: StatusError, Detail: err.Error()}
+	}
+
+	var options string
+	found := false
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 4 && fields[1] == r.Path {
+			options = fields[3]
+			found = true
+		}
+	}
+	if !found {
+		return Result{Status: StatusFail, Detail: "not a separate mount"}
+	}
+
+	present := make(map[string]bool)
+	for _, option := range strings.Split(options, ",") {
+		present[option] = true
+	}
+	var missing []string
+	for _, option := range r.Options {
+		if !present[option] {
+			missing = append(missing, option)
+		}
+	}
+	if len(missing) > 0 {
+		return Result{Status: StatusFail, Detail: "missing " + strings.Join(missing, ",")}
+	}
+	return Result{Status: StatusPass, Detail: options}
+}
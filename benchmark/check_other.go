@@ -0,0 +1,10 @@
+//go:build !unix
+
+package benchmark
+
+import "os"
+
+// fileOwner 非 Unix 平台没有 uid/gid，配置了属主属组的规则会判定为不符合
+func fileOwner(info os.FileInfo) (owner, group string) {
+	return "", ""
+}
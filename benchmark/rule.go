@@ -0,0 +1,171 @@
+// Package benchmark 从 YAML 加载声明式合规检查规则（CIS 风格）并执行。
+package benchmark
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 规则类型
+const (
+	TypeFileContent    = "file_content"    // 文件内容正则匹配
+	TypeFilePermission = "file_permission" // 文件权限与属主
+	TypeSysctl         = "sysctl"          // 内核参数值
+	TypeKernelModule   = "kernel_module"   // 内核模块加载或禁用
+	TypeService        = "service"         // 服务开机启用状态
+	TypeMountOption    = "mount_option"    // 挂载选项
+)
+
+// Rule 单条检查规则
+type Rule struct {
+	ID       string `yaml:"id"`
+	Title    string `yaml:"title"`
+	Severity string `yaml:"severity"` // high/medium/low，默认 medium
+	Type     string `yaml:"type"`
+
+	Path    string   `yaml:"path"`    // file_content/file_permission 的文件，mount_option 的挂载点
+	Pattern string   `yaml:"pattern"` // file_content 的正则，按行匹配（多行模式）
+	Expect  string   `yaml:"expect"`  // file_content: match（默认）/no_match
+	Mode    string   `yaml:"mode"`    // file_permission 允许的最大权限（八进制），如 0640
+	Owner   string   `yaml:"owner"`   // file_permission 的属主
+	Group   string   `yaml:"group"`   // file_permission 的属组
+	Key     string   `yaml:"key"`     // sysctl 参数名，如 net.ipv4.ip_forward
+	Value   string   `yaml:"value"`   // sysctl 期望值
+	Module  string   `yaml:"module"`  // kernel_module 模块名
+	Service string   `yaml:"service"` // service 服务名，不带后缀时视为 .service 单元
+	State   string   `yaml:"state"`   // kernel_module: loaded/not_loaded/blacklisted；service: enabled/disabled
+	Options []string `yaml:"options"` // mount_option 必须包含的挂载选项
+
+	pattern *regexp.Regexp
+	mode    uint32 // Unix 权限位
+}
+
+// ruleFile YAML 规则文件
+type ruleFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load 加载规则文件或目录下所有 .yaml/.yml 文件
+// 单条规则无效时跳过并在 errs 中说明，不影响其他规则
+func Load(path string) (rules []Rule, errs []string, err error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		files = nil
+		for _, pattern := range []string{"*.yaml", "*.yml"} {
+			matches, _ := filepath.Glob(filepath.Join(path, pattern))
+			files = append(files, matches...)
+		}
+		sort.Strings(files)
+	}
+
+	seen := make(map[string]bool)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		var parsed ruleFile
+		if err := yaml.Unmarshal(data, &parsed); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		for i := range parsed.Rules {
+			rule := parsed.Rules[i]
+			if err := rule.compile(); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: rule %q: %v", file, rule.ID, err))
+				continue
+			}
+			if seen[rule.ID] {
+				errs = append(errs, fmt.Sprintf("%s: duplicate rule id %q", file, rule.ID))
+				continue
+			}
+			seen[rule.ID] = true
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, errs, nil
+}
+
+// compile 校验规则并预编译正则与权限
+func (r *Rule) compile() error {
+	if r.ID == "" {
+		return fmt.Errorf("missing id")
+	}
+	if r.Title == "" {
+		r.Title = r.ID
+	}
+	switch r.Severity {
+	case "":
+		r.Severity = "medium"
+	case "high", "medium", "low":
+	default:
+		return fmt.Errorf("invalid severity %q", r.Severity)
+	}
+
+	switch r.Type {
+	case TypeFileContent:
+		if r.Path == "" || r.Pattern == "" {
+			return fmt.Errorf("file_content requires path and pattern")
+		}
+		pattern, err := regexp.Compile("(?m)" + r.Pattern)
+		if err != nil {
+			return err
+		}
+		r.pattern = pattern
+		if r.Expect == "" {
+			r.Expect = "match"
+		}
+		if r.Expect != "match" && r.Expect != "no_match" {
+			return fmt.Errorf("invalid expect %q", r.Expect)
+		}
+	case TypeFilePermission:
+		if r.Path == "" {
+			return fmt.Errorf("file_permission requires path")
+		}
+		if r.Mode != "" {
+			mode, err := strconv.ParseUint(r.Mode, 8, 32)
+			if err != nil {
+				return fmt.Errorf("invalid mode %q", r.Mode)
+			}
+			r.mode = uint32(mode)
+		}
+	case TypeSysctl:
+		if r.Key == "" {
+			return fmt.Errorf("sysctl requires key")
+		}
+	case TypeKernelModule:
+		if r.Module == "" {
+			return fmt.Errorf("kernel_module requires module")
+		}
+		if r.State != "loaded" && r.State != "not_loaded" && r.State != "blacklisted" {
+			return fmt.Errorf("invalid state %q", r.State)
+		}
+	case TypeService:
+		if r.Service == "" {
+			return fmt.Errorf("service requires service")
+		}
+		if r.State != "enabled" && r.State != "disabled" {
+			return fmt.Errorf("invalid state %q", r.State)
+		}
+	case TypeMountOption:
+		if r.Path == "" || len(r.Options) == 0 {
+			return fmt.Errorf("mount_option requires path and options")
+		}
+	default:
+		return fmt.Errorf("unknown type %q", r.Type)
+	}
+	return nil
+}
@@ -2,15 +2,20 @@ package cache
 
 import (
 	"host-monitor-agent/collector"
+	"host-monitor-agent/config"
 	"host-monitor-agent/models"
 	"log"
 	"sync"
 	"time"
 )
 
+// maxRecentEvents 缓存保留的最近事件数
+const maxRecentEvents = 500
+
 // MetricsCache 监控指标缓存
 type MetricsCache struct {
 	metrics   *models.HostMetrics
+	events    []models.Event
 	mutex     sync.RWMutex
 	collector *collector.MetricsCollector
 	interval  time.Duration
@@ -18,10 +23,10 @@ type MetricsCache struct {
 }
 
 // NewMetricsCache 创建缓存实例
-func NewMetricsCache(interval time.Duration) *MetricsCache {
+func NewMetricsCache(cfg config.CollectorConfig) *MetricsCache {
 	return &MetricsCache{
-		collector: collector.NewMetricsCollector(),
-		interval:  interval,
+		collector: collector.NewMetricsCollector(cfg),
+		interval:  cfg.Interval,
 		stopChan:  make(chan struct{}),
 	}
 }
@@ -68,6 +73,10 @@ func (c *MetricsCache) update() {
 	// 更新缓存
 	c.mutex.Lock()
 	c.metrics = metrics
+	c.events = append(c.events, metrics.Events...)
+	if len(c.events) > maxRecentEvents {
+		c.events = append([]models.Event(nil), c.events[len(c.events)-maxRecentEvents:]...)
+	}
 	c.mutex.Unlock()
 }
 
@@ -76,4 +85,13 @@ func (c *MetricsCache) Get() *models.HostMetrics {
 	c.mutex.RLock()
 	defer c.mutex.RUnlock()
 	return c.metrics
-}
\ No newline at end of file
+}
+
+// GetEvents 获取最近的事件（按时间先后）
+func (c *MetricsCache) GetEvents() []models.Event {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	events := make([]models.Event, len(c.events))
+	copy(events, c.events)
+	return events
+}
@@ -0,0 +1,318 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"host-monitor-agent/models"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 账户数据库路径
+const (
+	passwdFile  = "/etc/passwd"
+	groupFile   = "/etc/group"
+	shadowFile  = "/etc/shadow"
+	sudoersFile = "/etc/sudoers"
+)
+
+// passwdEntry /etc/passwd 中的一行
+type passwdEntry struct {
+	Name     string
+	Password string
+	UID      int
+	GID      int
+	Home     string
+	Shell    string
+}
+
+// groupEntry /etc/group 中的一行
+type groupEntry struct {
+	Name    string
+	GID     int
+	Members []string
+}
+
+// shadowEntry /etc/shadow 中的一行
+type shadowEntry struct {
+	Password   string
+	LastChange int // 自 1970-01-01 起的天数，-1 未设置
+	MaxDays    int // -1 未设置
+}
+
+// AccountCollector 本地用户与特权审计采集器
+type AccountCollector struct {
+	eventBuffer
+	lastUsers  map[string]bool
+	lastGroups map[string]bool
+}
+
+// Collect 审计账户、密码状态与 sudo 授权，并检测用户和组的增删
+func (a *AccountCollector) Collect() (interface{}, error) {
+	users, err := readPasswd(passwdFile)
+	if err != nil {
+		return nil, err
+	}
+	groups, _ := readGroups(groupFile)
+
+	shadow, err := readShadow(shadowFile)
+	shadowReadable := err == nil
+
+	sudoUsers, sudoGroups, err := readSudoers(sudoersFile)
+	sudoersReadable := err == nil
+
+	metrics := models.AccountMetrics{
+		ShadowReadable:  shadowReadable,
+		SudoersReadable: sudoersReadable,
+		Users:           len(users),
+		Groups:          len(groups),
+		LoginUsers:      []models.UserAccount{},
+		UID0Accounts:    []string{},
+		EmptyPassword:   []string{},
+		LockedPassword:  []string{},
+		SudoUsers:       []string{},
+		SudoGroups:      sudoGroups,
+	}
+
+	// 用户所属组：附加组成员与主组
+	groupNames := make(map[int]string, len(groups))
+	memberOf := make(map[string][]string)
+	for _, group := range groups {
+		groupNames[group.GID] = group.Name
+		for _, member := range group.Members {
+			memberOf[member] = append(memberOf[member], group.Name)
+		}
+	}
+
+	sudoGroupSet := make(map[string]bool, len(sudoGroups))
+	for _, group := range sudoGroups {
+		sudoGroupSet[group] = true
+	}
+
+	today := int(time.Now().Unix() / 86400)
+	for _, user := range users {
+		account := models.UserAccount{
+			Name:            user.Name,
+			UID:             user.UID,
+			GID:             user.GID,
+			Home:            user.Home,
+			Shell:           user.Shell,
+			LoginShell:      isLoginShell(user.Shell),
+			Password:        "unknown",
+			PasswordAgeDays: -1,
+			PasswordMaxDays: -1,
+			Groups:          []string{},
+		}
+		if primary, ok := groupNames[user.GID]; ok {
+			account.Groups = append(account.Groups, primary)
+		}
+		for _, group := range memberOf[user.Name] {
+			if group != groupNames[user.GID] {
+				account.Groups = append(account.Groups, group)
+			}
+		}
+
+		// passwd 中的密码字段不是 x 时不使用 shadow
+		password, known := user.Password, user.Password != "x"
+		if entry, ok := shadow[user.Name]; ok && !known {
+			password, known = entry.Password, true
+			if entry.LastChange >= 0 {
+				account.PasswordAgeDays = today - entry.LastChange
+			}
+			account.PasswordMaxDays = entry.MaxDays
+		}
+		if known {
+			account.Password = passwordStatus(password)
+		}
+
+		account.Sudo = sudoUsers[user.Name] || sudoUsers["#"+strconv.Itoa(user.UID)] || sudoUsers["ALL"]
+		for _, group := range account.Groups {
+			if sudoGroupSet[group] {
+				account.Sudo = true
+			}
+		}
+
+		if user.UID == 0 && user.Name != "root" {
+			metrics.UID0Accounts = append(metrics.UID0Accounts, user.Name)
+		}
+		switch account.Password {
+		case "empty":
+			metrics.EmptyPassword = append(metrics.EmptyPassword, user.Name)
+		case "locked":
+			if account.LoginShell {
+				// 系统账户普遍被锁定，只关注有登录 shell 的账户
+				metrics.LockedPassword = append(metrics.LockedPassword, user.Name)
+			}
+		}
+		if account.Sudo {
+			metrics.SudoUsers = append(metrics.SudoUsers, user.Name)
+		}
+		if account.LoginShell || account.UID == 0 || account.Sudo {
+			metrics.LoginUsers = append(metrics.LoginUsers, account)
+		}
+	}
+
+	currentUsers := make(map[string]bool, len(users))
+	for _, user := range users {
+		currentUsers[user.Name] = true
+	}
+	currentGroups := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		currentGroups[group.Name] = true
+	}
+	metrics.UsersAdded, metrics.UsersRemoved = a.detectChanges("user", a.lastUsers, currentUsers)
+	metrics.GroupsAdded, metrics.GroupsRemoved = a.detectChanges("group", a.lastGroups, currentGroups)
+	a.lastUsers = currentUsers
+	a.lastGroups = currentGroups
+
+	return metrics, nil
+}
+
+// detectChanges 对比上一次的名称集合，产生 <kind>_added/<kind>_removed 事件
+// 首次采集只建立基线，不产生事件
+func (a *AccountCollector) detectChanges(kind string, last, current map[string]bool) ([]string, []string) {
+	added, removed := []string{}, []string{}
+	if last == nil {
+		return added, removed
+	}
+
+	for name := range current {
+		if !last[name] {
+			added = append(added, name)
+		}
+	}
+	for name := range last {
+		if !current[name] {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	for _, name := range added {
+		a.emit("accounts", kind+"_added", fmt.Sprintf("%s %s added", kind, name), map[string]string{kind: name})
+	}
+	for _, name := range removed {
+		a.emit("accounts", kind+"_removed", fmt.Sprintf("%s %s removed", kind, name), map[string]string{kind: name})
+	}
+	return added, removed
+}
+
+// passwordStatus 根据密码字段判断状态
+// "!" 开头为 passwd -l 锁定，"*" 与 "!!" 表示未设置密码
+func passwordStatus(password string) string {
+	switch {
+	case password == "":
+		return "empty"
+	case strings.HasPrefix(password, "!"), strings.HasPrefix(password, "*"):
+		return "locked"
+	default:
+		return "set"
+	}
+}
+
+// isLoginShell 排除 nologin/false 等禁止交互登录的 shell
+func isLoginShell(shell string) bool {
+	switch filepath.Base(shell) {
+	case "", "nologin", "false", "sync", "shutdown", "halt":
+		return false
+	}
+	return true
+}
+
+// readColonFile 读取冒号分隔的账户数据库，跳过空行、注释与 NIS 条目
+func readColonFile(path string, minFields int) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rows [][]string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		fields := strings.Split(line, ":")
+		if len(fields) < minFields {
+			continue
+		}
+		rows = append(rows, fields)
+	}
+	return rows, scanner.Err()
+}
+
+// readPasswd 解析 /etc/passwd
+func readPasswd(path string) ([]passwdEntry, error) {
+	rows, err := readColonFile(path, 7)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]passwdEntry, 0, len(rows))
+	for _, fields := range rows {
+		uid, err1 := strconv.Atoi(fields[2])
+		gid, err2 := strconv.Atoi(fields[3])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		users = append(users, passwdEntry{
+			Name:     fields[0],
+			Password: fields[1],
+			UID:      uid,
+			GID:      gid,
+			Home:     fields[5],
+			Shell:    fields[6],
+		})
+	}
+	return users, nil
+}
+
+// readGroups 解析 /etc/group
+func readGroups(path string) ([]groupEntry, error) {
+	rows, err := readColonFile(path, 4)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]groupEntry, 0, len(rows))
+	for _, fields := range rows {
+		gid, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		group := groupEntry{Name: fields[0], GID: gid}
+		for _, member := range strings.Split(fields[3], ",") {
+			if member = strings.TrimSpace(member); member != "" {
+				group.Members = append(group.Members, member)
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// readShadow 解析 /etc/shadow，按用户名索引
+func readShadow(path string) (map[string]shadowEntry, error) {
+	rows, err := readColonFile(path, 5)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]shadowEntry, len(rows))
+	for _, fields := range rows {
+		entry := shadowEntry{Password: fields[1], LastChange: -1, MaxDays: -1}
+		if n, err := strconv.Atoi(fields[2]); err == nil {
+			entry.LastChange = n
+		}
+		// 99999 为默认的"永不过期"
+		if n, err := strconv.Atoi(fields[4]); err == nil && n < 99999 {
+			entry.MaxDays = n
+		}
+		entries[fields[0]] = entry
+	}
+	return entries, nil
+}
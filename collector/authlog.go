@@ -0,0 +1,356 @@
+package collector
+
+import (
+	"host-monitor-agent/models"
+	"host-monitor-agent/tailer"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// authTopN 窗口内攻击来源与目标用户的排行数量
+const authTopN = 10
+
+// sshPairTimeout "Invalid user" 行等待同一 sshd 进程 "Failed ... for invalid user" 行的最长时间
+// 超过 sshd 默认的 LoginGraceTime（120 秒）即不会再出现
+const sshPairTimeout = 5 * time.Minute
+
+var (
+	// rsyslog 合并重复行: "message repeated 3 times: [ Failed password for ...]"
+	authRepeatedRe = regexp.MustCompile(`message repeated (\d+) times: \[ ?(.*)\]$`)
+	// OpenSSH 9.8 起认证日志来自 sshd-session
+	sshFailedRe   = regexp.MustCompile(`sshd(?:-session)?(?:\[(\d+)\])?: Failed (\S+) for (invalid user )?(\S*) from (\S+) port`)
+	sshAcceptedRe = regexp.MustCompile(`sshd(?:-session)?(?:\[(\d+)\])?: Accepted (\S+) for (\S+) from (\S+) port`)
+	sshInvalidRe  = regexp.MustCompile(`sshd(?:-session)?(?:\[(\d+)\])?: Invalid user (\S*) ?from (\S+)`)
+	sudoRe        = regexp.MustCompile(`sudo(?:\[\d+\])?: +(\S+) : (.*)`)
+	suOpenedRe    = regexp.MustCompile(`pam_unix\(su(?:-l)?:session\): session opened for user (\S+)`)
+	suFailedRe    = regexp.MustCompile(`pam_unix\(su(?:-l)?:auth\): authentication failure|FAILED SU|FAILED su`)
+)
+
+// authAnalyzer 认证日志分析器：累计计数与滑动窗口计数
+type authAnalyzer struct {
+	ssh  models.SSHAuthMetrics
+	sudo models.PrivilegeMetrics
+	su   models.PrivilegeMetrics
+
+	// SSH 窗口计数的键为 "IP\x00用户"
+	sshFailures  *tailer.WindowCounter
+	sshSuccesses *tailer.WindowCounter
+	sshInvalid   *tailer.WindowCounter
+	sshAttempts  *tailer.WindowCounter
+	sudoOK       *tailer.WindowCounter
+	sudoFailed   *tailer.WindowCounter
+	suOK         *tailer.WindowCounter
+	suFailed     *tailer.WindowCounter
+
+	// 尚未出现对应 "Failed" 行的不存在用户尝试，键为 sshd PID
+	invalidPending map[string]pendingInvalid
+}
+
+// pendingInvalid 等待配对的 "Invalid user" 行
+type pendingInvalid struct {
+	ip   string
+	seen time.Time
+}
+
+// newAuthAnalyzer 创建指定窗口长度的分析器
+func newAuthAnalyzer(window time.Duration) *authAnalyzer {
+	return &authAnalyzer{
+		ssh: models.SSHAuthMetrics{
+			FailuresByMethod:  map[string]uint64{},
+			SuccessesByMethod: map[string]uint64{},
+		},
+		sshFailures:  tailer.NewWindowCounter(window),
+		sshSuccesses: tailer.NewWindowCounter(window),
+		sshInvalid:   tailer.NewWindowCounter(window),
+		sshAttempts:  tailer.NewWindowCounter(window),
+		sudoOK:       tailer.NewWindowCounter(window),
+		sudoFailed:   tailer.NewWindowCounter(window),
+		suOK:         tailer.NewWindowCounter(window),
+		suFailed:     tailer.NewWindowCounter(window),
+
+		invalidPending: make(map[string]pendingInvalid),
+	}
+}
+
+// authEvent 从单行日志中识别出的认证事件
+type authEvent struct {
+	Kind     string // ssh_failed/ssh_accepted/ssh_invalid_user/sudo/sudo_failed/su/su_failed
+	User     string
+	IP       string
+	Method   string
+	PID      string    // sshd 进程号，同一连接的各行相同
+	Invalid  bool      // "Failed ... for invalid user"
+	Count    uint64    // "message repeated N times" 时大于1
+	Attempts uint64    // 计入的认证尝试次数，由 authAnalyzer.observe 填写
+	Time     time.Time // 日志行自带的时间，无法解析时为零值
+}
+
+// parseAuthLine 解析 sshd/sudo/su 日志行，无法识别时返回 false
+func parseAuthLine(line string) (authEvent, bool) {
+	event := authEvent{Count: 1, Time: parseSyslogTime(line, time.Now())}
+	if m := authRepeatedRe.FindStringSubmatch(line); m != nil {
+		if n, err := strconv.ParseUint(m[1], 10, 64); err == nil && n > 0 {
+			event.Count = n
+		}
+		line = m[2]
+	}
+
+	if m := sshFailedRe.FindStringSubmatch(line); m != nil {
+		event.Kind = "ssh_failed"
+		event.PID = m[1]
+		// keyboard-interactive/pam 归并为 keyboard-interactive
+		event.Method, _, _ = strings.Cut(m[2], "/")
+		event.Invalid = m[3] != ""
+		event.User = m[4]
+		event.IP = m[5]
+		return event, true
+	}
+	if m := sshAcceptedRe.FindStringSubmatch(line); m != nil {
+		event.Kind = "ssh_accepted"
+		event.PID = m[1]
+		event.Method, _, _ = strings.Cut(m[2], "/")
+		event.User = m[3]
+		event.IP = m[4]
+		return event, true
+	}
+	if m := sshInvalidRe.FindStringSubmatch(line); m != nil {
+		event.Kind = "ssh_invalid_user"
+		event.PID = m[1]
+		event.User = m[2]
+		event.IP = m[3]
+		return event, true
+	}
+	if m := sudoRe.FindStringSubmatch(line); m != nil {
+		event.User = m[1]
+		detail := m[2]
+		switch {
+		case strings.Contains(detail, "incorrect password attempt"),
+			strings.Contains(detail, "NOT in sudoers"),
+			strings.Contains(detail, "command not allowed"):
+			event.Kind = "sudo_failed"
+		case strings.Contains(detail, "COMMAND="):
+			event.Kind = "sudo"
+		default:
+			return event, false
+		}
+		return event, true
+	}
+	if m := suOpenedRe.FindStringSubmatch(line); m != nil {
+		event.Kind = "su"
+		event.User = m[1]
+		return event, true
+	}
+	if suFailedRe.MatchString(line) {
+		event.Kind = "su_failed"
+		return event, true
+	}
+
+	return event, false
+}
+
+// parseSyslogTime 解析日志行开头的时间戳，支持 RFC3339（rsyslog 高精度格式）
+// 与传统的 "Jan _2 15:04:05"；后者没有年份，取不晚于 now 的最近一年
+func parseSyslogTime(line string, now time.Time) time.Time {
+	if field, _, ok := strings.Cut(line, " "); ok {
+		if t, err := time.Parse(time.RFC3339Nano, field); err == nil {
+			return t
+		}
+	}
+	if len(line) < len(time.Stamp) {
+		return time.Time{}
+	}
+	t, err := time.ParseInLocation(time.Stamp, line[:len(time.Stamp)], time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	t = t.AddDate(now.Year(), 0, 0)
+	// 跨年：12月的日志在1月读取
+	if t.After(now.Add(24 * time.Hour)) {
+		t = t.AddDate(-1, 0, 0)
+	}
+	return t
+}
+
+// observe 处理一行认证日志，返回识别出的事件
+// 窗口计数按日志行的时间计入，重启或轮转后补读的旧日志不会挤进当前窗口
+func (a *authAnalyzer) observe(line string) (authEvent, bool) {
+	event, ok := parseAuthLine(line)
+	if !ok {
+		return event, false
+	}
+
+	key := event.IP + "\x00" + event.User
+	switch event.Kind {
+	case "ssh_failed":
+		a.ssh.Failures += event.Count
+		a.ssh.FailuresByMethod[event.Method] += event.Count
+		a.sshFailures.AddAt(event.Time, key, event.Count)
+		event.Attempts = event.Count
+		if event.Invalid && a.pairInvalid(event) {
+			// 与同一连接的 "Invalid user" 行是同一次尝试，已在该行计入
+			event.Attempts--
+		}
+		a.sshAttempts.AddAt(event.Time, key, event.Attempts)
+	case "ssh_accepted":
+		a.ssh.Successes += event.Count
+		a.ssh.SuccessesByMethod[event.Method] += event.Count
+		a.sshSuccesses.AddAt(event.Time, key, event.Count)
+	case "ssh_invalid_user":
+		a.ssh.InvalidUsers += event.Count
+		a.sshInvalid.AddAt(event.Time, key, event.Count)
+		// 仅密钥认证时不存在用户的尝试只有 "Invalid user" 一行，在此计入尝试次数
+		event.Attempts = event.Count
+		a.sshAttempts.AddAt(event.Time, key, event.Attempts)
+		if event.PID != "" {
+			a.invalidPending[event.PID] = pendingInvalid{ip: event.IP, seen: time.Now()}
+		}
+	case "sudo":
+		a.sudo.Invocations += event.Count
+		a.sudoOK.AddAt(event.Time, event.User, event.Count)
+	case "sudo_failed":
+		a.sudo.Failures += event.Count
+		a.sudoFailed.AddAt(event.Time, event.User, event.Count)
+	case "su":
+		a.su.Invocations += event.Count
+		a.suOK.AddAt(event.Time, event.User, event.Count)
+	case "su_failed":
+		a.su.Failures += event.Count
+		a.suFailed.AddAt(event.Time, event.User, event.Count)
+	}
+	return event, true
+}
+
+// pairInvalid 查找同一 sshd 进程尚未配对的 "Invalid user" 行，找到时消耗该行并返回 true
+// 同一连接的多次密码尝试只有第一条 "Failed" 行与之配对
+func (a *authAnalyzer) pairInvalid(event authEvent) bool {
+	pending, ok := a.invalidPending[event.PID]
+	if !ok || event.PID == "" || pending.ip != event.IP {
+		return false
+	}
+	delete(a.invalidPending, event.PID)
+	return true
+}
+
+// fill 将累计值、窗口值与排行写入安全指标
+func (a *authAnalyzer) fill(metrics *models.SecurityMetrics) {
+	for pid, pending := range a.invalidPending {
+		if time.Since(pending.seen) > sshPairTimeout {
+			delete(a.invalidPending, pid)
+		}
+	}
+
+	metrics.SSH = a.ssh
+	metrics.SSH.FailuresByMethod = copyCounts(a.ssh.FailuresByMethod)
+	metrics.SSH.SuccessesByMethod = copyCounts(a.ssh.SuccessesByMethod)
+	metrics.SSH.FailuresWindow = a.sshFailures.Count()
+	metrics.SSH.SuccessesWindow = a.sshSuccesses.Count()
+	metrics.SSH.InvalidUsersWindow = a.sshInvalid.Count()
+
+	metrics.Sudo = a.sudo
+	metrics.Sudo.InvocationsWindow = a.sudoOK.Count()
+	metrics.Sudo.FailuresWindow = a.sudoFailed.Count()
+	metrics.Su = a.su
+	metrics.Su.InvocationsWindow = a.suOK.Count()
+	metrics.Su.FailuresWindow = a.suFailed.Count()
+
+	failures := a.sshFailures.CountByKey()
+	successes := a.sshSuccesses.CountByKey()
+	invalid := a.sshInvalid.CountByKey()
+	attempts := a.sshAttempts.CountByKey()
+
+	sources := make(map[string]*models.AuthSource)
+	sourceUsers := make(map[string]map[string]bool)
+	targets := make(map[string]*models.AuthTarget)
+	targetIPs := make(map[string]map[string]bool)
+
+	source := func(ip string) *models.AuthSource {
+		if sources[ip] == nil {
+			sources[ip] = &models.AuthSource{IP: ip}
+			sourceUsers[ip] = map[string]bool{}
+		}
+		return sources[ip]
+	}
+	target := func(user string) *models.AuthTarget {
+		if targets[user] == nil {
+			targets[user] = &models.AuthTarget{User: user}
+			targetIPs[user] = map[string]bool{}
+		}
+		return targets[user]
+	}
+
+	for key, n := range failures {
+		ip, user, _ := strings.Cut(key, "\x00")
+		source(ip).Failures += n
+		sourceUsers[ip][user] = true
+		target(user).Failures += n
+		targetIPs[user][ip] = true
+	}
+	for key, n := range invalid {
+		ip, user, _ := strings.Cut(key, "\x00")
+		source(ip).InvalidUsers += n
+		sourceUsers[ip][user] = true
+	}
+	for key, n := range attempts {
+		ip, _, _ := strings.Cut(key, "\x00")
+		source(ip).Attempts += n
+	}
+	for key, n := range successes {
+		ip, user, _ := strings.Cut(key, "\x00")
+		source(ip).Successes += n
+		target(user).Successes += n
+	}
+
+	metrics.TopAttackers = []models.AuthSource{}
+	for ip, s := range sources {
+		if s.Attempts == 0 {
+			continue
+		}
+		s.Users = len(sourceUsers[ip])
+		metrics.TopAttackers = append(metrics.TopAttackers, *s)
+	}
+	// 按尝试次数排序：仅密钥认证时扫描不存在用户的来源没有 "Failed" 行，只看 Failures 会排在最后
+	sort.Slice(metrics.TopAttackers, func(i, j int) bool {
+		a, b := metrics.TopAttackers[i], metrics.TopAttackers[j]
+		if a.Attempts != b.Attempts {
+			return a.Attempts > b.Attempts
+		}
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.IP < b.IP
+	})
+	if len(metrics.TopAttackers) > authTopN {
+		metrics.TopAttackers = metrics.TopAttackers[:authTopN]
+	}
+
+	metrics.TopTargetUsers = []models.AuthTarget{}
+	for user, t := range targets {
+		if t.Failures == 0 {
+			continue
+		}
+		t.SourceIPs = len(targetIPs[user])
+		metrics.TopTargetUsers = append(metrics.TopTargetUsers, *t)
+	}
+	sort.Slice(metrics.TopTargetUsers, func(i, j int) bool {
+		a, b := metrics.TopTargetUsers[i], metrics.TopTargetUsers[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.User < b.User
+	})
+	if len(metrics.TopTargetUsers) > authTopN {
+		metrics.TopTargetUsers = metrics.TopTargetUsers[:authTopN]
+	}
+}
+
+// copyCounts 复制计数表，避免缓存中的指标与采集器共享 map
+func copyCounts(counts map[string]uint64) map[string]uint64 {
+	result := make(map[string]uint64, len(counts))
+	for k, v := range counts {
+		result[k] = v
+	}
+	return result
+}
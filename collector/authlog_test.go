@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"host-monitor-agent/models"
+)
+
+// authLine 构造带当前时间的 sshd 日志行
+func authLine(format string) string {
+	return time.Now().Format(time.RFC3339) + " host " + format
+}
+
+func TestAuthAnalyzerAttempts(t *testing.T) {
+	a := newAuthAnalyzer(5 * time.Minute)
+	lines := []string{
+		// 仅密钥认证：不存在用户只有 "Invalid user" 行
+		"sshd[101]: Invalid user admin from 198.51.100.7 port 40001",
+		"sshd[102]: Invalid user oracle from 198.51.100.7 port 40002",
+		"sshd[103]: Invalid user test from 198.51.100.7 port 40003",
+		// 密码认证：同一连接的 "Invalid user" 与首条 "Failed" 是同一次尝试，之后的 "Failed" 是新的尝试
+		"sshd[201]: Invalid user guest from 203.0.113.9 port 50001",
+		"sshd[201]: Failed password for invalid user guest from 203.0.113.9 port 50001 ssh2",
+		"sshd[201]: Failed password for invalid user guest from 203.0.113.9 port 50001 ssh2",
+		// 存在的用户
+		"sshd[301]: Failed password for root from 192.0.2.1 port 60001 ssh2",
+	}
+	for _, line := range lines {
+		a.observe(authLine(line))
+	}
+
+	var metrics models.SecurityMetrics
+	a.fill(&metrics)
+
+	want := []struct {
+		ip                 string
+		attempts, failures uint64
+	}{
+		{"198.51.100.7", 3, 0},
+		{"203.0.113.9", 2, 2},
+		{"192.0.2.1", 1, 1},
+	}
+	if len(metrics.TopAttackers) != len(want) {
+		t.Fatalf("TopAttackers = %+v", metrics.TopAttackers)
+	}
+	for i, w := range want {
+		got := metrics.TopAttackers[i]
+		if got.IP != w.ip || got.Attempts != w.attempts || got.Failures != w.failures {
+			t.Errorf("TopAttackers[%d] = %+v, want ip %s attempts %d failures %d", i, got, w.ip, w.attempts, w.failures)
+		}
+	}
+}
+
+func TestAuthAnalyzerIgnoresOldLines(t *testing.T) {
+	a := newAuthAnalyzer(5 * time.Minute)
+	// 重启后补读的一小时前的日志只计入累计值
+	old := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	a.observe(old + " host sshd[1]: Failed password for root from 192.0.2.1 port 1 ssh2")
+
+	var metrics models.SecurityMetrics
+	a.fill(&metrics)
+	if metrics.SSH.Failures != 1 || metrics.SSH.FailuresWindow != 0 || len(metrics.TopAttackers) != 0 {
+		t.Errorf("ssh = %+v, top = %+v", metrics.SSH, metrics.TopAttackers)
+	}
+}
+
+func TestParseSyslogTime(t *testing.T) {
+	now := time.Date(2026, 1, 2, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		line string
+		want time.Time
+	}{
+		{"Jan  2 09:59:00 host sshd[1]: x", time.Date(2026, 1, 2, 9, 59, 0, 0, time.Local)},
+		// 12 月的日志在 1 月读取
+		{"Dec 31 23:00:00 host sshd[1]: x", time.Date(2025, 12, 31, 23, 0, 0, 0, time.Local)},
+		{"2026-01-02T09:00:00.123456+00:00 host sshd[1]: x", time.Date(2026, 1, 2, 9, 0, 0, 123456000, time.UTC)},
+		{"garbage", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseSyslogTime(tt.line, now); !got.Equal(tt.want) {
+			t.Errorf("parseSyslogTime(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package collector
+
+import (
+	"encoding/json"
+	"fmt"
+	"host-monitor-agent/benchmark"
+	"host-monitor-agent/models"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// benchmarkHistorySize 保留的历史检查次数（按小时检查约一周）
+const benchmarkHistorySize = 168
+
+// ruleState 规则的上一次状态
+type ruleState struct {
+	Status string `json:"status"`
+	Since  string `json:"since"`
+}
+
+// benchmarkState 持久化的检查历史与规则状态，重启后仍能识别状态变化
+type benchmarkState struct {
+	History []models.ComplianceRun `json:"history"` // 新的在后
+	Rules   map[string]ruleState   `json:"rules"`
+}
+
+// BenchmarkCollector 合规基线检查采集器
+type BenchmarkCollector struct {
+	RulesPath string        // 规则文件或目录
+	Interval  time.Duration // 检查间隔
+	StateDir  string        // 历史保存目录
+
+	eventBuffer
+	state   *benchmarkState
+	lastRun time.Time
+	last    models.ComplianceMetrics
+}
+
+// Collect 按间隔加载规则并执行，规则状态变化作为事件发出
+func (b *BenchmarkCollector) Collect() (interface{}, error) {
+	if !b.lastRun.IsZero() && time.Since(b.lastRun) < b.Interval {
+		return b.last, nil
+	}
+	b.lastRun = time.Now()
+
+	metrics := models.ComplianceMetrics{
+		RulesPath:  b.RulesPath,
+		Results:    []models.RuleResult{},
+		LoadErrors: []string{},
+		History:    []models.ComplianceRun{},
+	}
+
+	rules, loadErrors, err := benchmark.Load(b.RulesPath)
+	if err != nil {
+		// 没有规则文件时关闭
+		b.last = metrics
+		return metrics, nil
+	}
+	metrics.Enabled = true
+	if loadErrors != nil {
+		metrics.LoadErrors = loadErrors
+	}
+
+	if b.state == nil {
+		b.state = b.loadState()
+	}
+
+	now := time.Now().UTC().Format("2006-01-02 15:04:05 MST")
+	rulesSeen := make(map[string]ruleState, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		result := rule.Evaluate()
+
+		state, known := b.state.Rules[rule.ID]
+		if !known || state.Status != result.Status {
+			if known {
+				b.emitTransition(rule, state.Status, result)
+			}
+			state = ruleState{Status: result.Status, Since: now}
+		}
+		rulesSeen[rule.ID] = state
+
+		switch result.Status {
+		case benchmark.StatusPass:
+			metrics.Passed++
+		case benchmark.StatusFail:
+			metrics.Failed++
+		default:
+			metrics.Errors++
+		}
+
+		metrics.Results = append(metrics.Results, models.RuleResult{
+			ID:       rule.ID,
+			Title:    rule.Title,
+			Severity: rule.Severity,
+			Type:     rule.Type,
+			Status:   result.Status,
+			Detail:   result.Detail,
+			Since:    state.Since,
+		})
+	}
+	b.state.Rules = rulesSeen
+
+	metrics.Rules = len(rules)
+	metrics.LastRun = now
+	if checked := metrics.Passed + metrics.Failed; checked > 0 {
+		metrics.Score = math.Round(float64(metrics.Passed)/float64(checked)*1000) / 10
+	}
+
+	b.state.History = appendBounded(b.state.History, models.ComplianceRun{
+		Time:   now,
+		Score:  metrics.Score,
+		Passed: metrics.Passed,
+		Failed: metrics.Failed,
+		Errors: metrics.Errors,
+	}, benchmarkHistorySize)
+	metrics.History = reversed(b.state.History)
+
+	// 历史写入失败不影响本次结果
+	b.saveState()
+
+	b.last = metrics
+	return metrics, nil
+}
+
+// transitionEvents 规则新状态对应的事件类型
+var transitionEvents = map[string]string{
+	benchmark.StatusPass:  "rule_passed",
+	benchmark.StatusFail:  "rule_failed",
+	benchmark.StatusError: "rule_error",
+}
+
+// emitTransition 规则状态变化时发出 rule_passed/rule_failed/rule_error 事件
+func (b *BenchmarkCollector) emitTransition(rule *benchmark.Rule, previous string, result benchmark.Result) {
+	b.emit("benchmark", transitionEvents[result.Status],
+		fmt.Sprintf("%s changed from %s to %s: %s", rule.ID, previous, result.Status, result.Detail),
+		map[string]string{
+			"rule":     rule.ID,
+			"title":    rule.Title,
+			"severity": rule.Severity,
+			"previous": previous,
+			"status":   result.Status,
+			"detail":   result.Detail,
+		})
+}
+
+// stateFile 历史文件路径
+func (b *BenchmarkCollector) stateFile() string {
+	return filepath.Join(b.StateDir, "benchmark-state.json")
+}
+
+// loadState 读取检查历史，不存在或损坏时从空白开始
+func (b *BenchmarkCollector) loadState() *benchmarkState {
+	state := &benchmarkState{Rules: make(map[string]ruleState)}
+	data, err := os.ReadFile(b.stateFile())
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, state); err != nil || state.Rules == nil {
+		return &benchmarkState{Rules: make(map[string]ruleState)}
+	}
+	return state
+}
+
+// saveState 将检查历史写入磁盘（先写临时文件再改名）
+func (b *BenchmarkCollector) saveState() error {
+	data, err := json.Marshal(b.state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(b.StateDir, 0755); err != nil {
+		return err
+	}
+	tmp := b.stateFile() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.stateFile())
+}
@@ -0,0 +1,404 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"host-monitor-agent/models"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procNetBonding bonding 驱动为每个 bond 接口生成一个状态文件
+const procNetBonding = "/proc/net/bonding"
+
+// teamdRunDirs teamd 的 pid 文件与控制套接字目录，文件名即 team 接口名
+var teamdRunDirs = []string{"/run/teamd", "/var/run/teamd"}
+
+// teamdctlTimeout teamdctl 查询超时
+const teamdctlTimeout = 5 * time.Second
+
+// bondModes /proc/net/bonding 中模式描述与 mode 参数名的对应关系
+var bondModes = []struct{ prefix, mode string }{
+	{"load balancing (round-robin)", "balance-rr"},
+	{"fault-tolerance (active-backup)", "active-backup"},
+	{"load balancing (xor)", "balance-xor"},
+	{"fault-tolerance (broadcast)", "broadcast"},
+	{"IEEE 802.3ad Dynamic link aggregation", "802.3ad"},
+	{"transmit load balancing", "balance-tlb"},
+	{"adaptive load balancing", "balance-alb"},
+}
+
+// BondCollector bonding/team 接口健康采集器
+// 主备模式下备用链路故障时聚合接口的流量不受影响，需要逐个成员检查
+type BondCollector struct {
+	eventBuffer
+
+	// 上一次采集的冗余状态与活动成员，用于产生降级/恢复/切换事件
+	lastReduced map[string]bool
+	lastActive  map[string]string
+}
+
+// Collect 采集所有 bonding 与 team 接口的成员状态
+func (b *BondCollector) Collect() (interface{}, error) {
+	bonds := []models.BondMetrics{}
+
+	entries, err := os.ReadDir(procNetBonding)
+	if err != nil && !os.IsNotExist(err) {
+		return bonds, err
+	}
+	for _, entry := range entries {
+		bond, err := readBond(filepath.Join(procNetBonding, entry.Name()))
+		if err != nil {
+			bonds = append(bonds, models.BondMetrics{
+				Name:    entry.Name(),
+				Driver:  "bonding",
+				Members: []models.BondSlave{},
+				Error:   err.Error(),
+			})
+			continue
+		}
+		bonds = append(bonds, bond)
+	}
+
+	for _, name := range teamDevices() {
+		bond, err := readTeam(name)
+		if err != nil {
+			bonds = append(bonds, models.BondMetrics{
+				Name:    name,
+				Driver:  "team",
+				Members: []models.BondSlave{},
+				Error:   err.Error(),
+			})
+			continue
+		}
+		bonds = append(bonds, bond)
+	}
+
+	sort.Slice(bonds, func(i, j int) bool {
+		return bonds[i].Name < bonds[j].Name
+	})
+
+	b.detectChanges(bonds)
+
+	return bonds, nil
+}
+
+// detectChanges 冗余降低/恢复时产生 bond_degraded/bond_recovered 事件，
+// 活动成员变化时产生 bond_failover 事件；首次采集只建立基线
+func (b *BondCollector) detectChanges(bonds []models.BondMetrics) {
+	reduced := make(map[string]bool, len(bonds))
+	active := make(map[string]string, len(bonds))
+	for _, bond := range bonds {
+		if bond.Error != "" {
+			continue
+		}
+		reduced[bond.Name] = bond.ReducedRedundancy
+		active[bond.Name] = bond.ActiveSlave
+
+		if b.lastReduced == nil {
+			continue
+		}
+		attrs := map[string]string{
+			"interface":  bond.Name,
+			"driver":     bond.Driver,
+			"mode":       bond.Mode,
+			"slaves":     strconv.Itoa(bond.Slaves),
+			"up_slaves":  strconv.Itoa(bond.UpSlaves),
+			"mii_status": bond.MIIStatus,
+		}
+		if wasReduced, ok := b.lastReduced[bond.Name]; ok && wasReduced != bond.ReducedRedundancy {
+			if bond.ReducedRedundancy {
+				attrs["down_slaves"] = strings.Join(downSlaves(bond), ",")
+				b.emit("network", "bond_degraded",
+					fmt.Sprintf("%s running with reduced redundancy: %d of %d slaves up", bond.Name, bond.UpSlaves, bond.Slaves), attrs)
+			} else {
+				b.emit("network", "bond_recovered",
+					fmt.Sprintf("%s redundancy restored: %d of %d slaves up", bond.Name, bond.UpSlaves, bond.Slaves), attrs)
+			}
+		}
+		if previous, ok := b.lastActive[bond.Name]; ok && previous != bond.ActiveSlave && previous != "" {
+			failover := map[string]string{
+				"interface": bond.Name,
+				"driver":    bond.Driver,
+				"previous":  previous,
+				"current":   bond.ActiveSlave,
+			}
+			b.emit("network", "bond_failover",
+				fmt.Sprintf("%s active slave changed from %s to %s", bond.Name, previous, bond.ActiveSlave), failover)
+		}
+	}
+	b.lastReduced = reduced
+	b.lastActive = active
+}
+
+// downSlaves 不可用的成员接口名
+func downSlaves(bond models.BondMetrics) []string {
+	var names []string
+	for _, member := range bond.Members {
+		if !slaveUsable(bond, member) {
+			names = append(names, member.Interface)
+		}
+	}
+	return names
+}
+
+// readBond 解析 /proc/net/bonding/<bond>
+//
+//	Bonding Mode: fault-tolerance (active-backup)
+//	Currently Active Slave: eth0
+//	MII Status: up
+//
+//	Slave Interface: eth0
+//	MII Status: up
+//	Speed: 10000 Mbps
+//	Duplex: full
+//	Link Failure Count: 0
+//
+// 802.3ad 模式在 bond 段的 "Active Aggregator Info" 中给出活动聚合组 ID，
+// 成员段的 Aggregator ID 与之不同时该成员不承载流量
+func readBond(path string) (models.BondMetrics, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return models.BondMetrics{}, err
+	}
+	defer file.Close()
+
+	bond := models.BondMetrics{
+		Name:    filepath.Base(path),
+		Driver:  "bonding",
+		Members: []models.BondSlave{},
+	}
+	activeAggregator := 0
+	var slave *models.BondSlave
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		if key == "Slave Interface" {
+			bond.Members = append(bond.Members, models.BondSlave{Interface: value, SpeedMbps: -1})
+			slave = &bond.Members[len(bond.Members)-1]
+			continue
+		}
+
+		// 第一个 Slave Interface 之前为 bond 自身的属性
+		if slave == nil {
+			switch key {
+			case "Bonding Mode":
+				bond.Mode = bondModeName(value)
+			case "Currently Active Slave":
+				if value != "None" {
+					bond.ActiveSlave = value
+				}
+			case "MII Status":
+				bond.MIIStatus = value
+			case "Aggregator ID":
+				activeAggregator, _ = strconv.Atoi(value)
+			}
+			continue
+		}
+
+		switch key {
+		case "MII Status":
+			slave.MIIStatus = value
+		case "Speed":
+			if speed, err := strconv.ParseInt(strings.TrimSuffix(value, " Mbps"), 10, 64); err == nil {
+				slave.SpeedMbps = speed
+			}
+		case "Duplex":
+			slave.Duplex = value
+		case "Link Failure Count":
+			slave.LinkFailures, _ = strconv.ParseUint(value, 10, 64)
+		case "Aggregator ID":
+			// 成员段的 LACP 细节中还会出现同名字段，只取第一个
+			if slave.AggregatorID == 0 {
+				slave.AggregatorID, _ = strconv.Atoi(value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return bond, err
+	}
+
+	for i := range bond.Members {
+		member := &bond.Members[i]
+		// tlb/alb 的所有成员都发送流量，Currently Active Slave 只表示接收链路
+		switch bond.Mode {
+		case "active-backup":
+			member.Active = member.MIIStatus == "up" && member.Interface == bond.ActiveSlave
+		case "802.3ad":
+			member.Active = member.MIIStatus == "up" && member.AggregatorID == activeAggregator
+		default:
+			member.Active = member.MIIStatus == "up"
+		}
+	}
+
+	summarizeBond(&bond)
+	return bond, nil
+}
+
+// bondModeName 将模式描述转换为 mode 参数名，未知描述原样返回
+func bondModeName(description string) string {
+	for _, m := range bondModes {
+		if strings.HasPrefix(description, m.prefix) {
+			return m.mode
+		}
+	}
+	return description
+}
+
+// slaveUsable 成员链路正常且能承载流量
+// 802.3ad 中不属于活动聚合组的成员（如对端未配置 LACP）即使链路正常也不可用
+func slaveUsable(bond models.BondMetrics, member models.BondSlave) bool {
+	if member.MIIStatus != "up" {
+		return false
+	}
+	if bond.Mode == "802.3ad" || bond.Mode == "lacp" {
+		return member.Active
+	}
+	return true
+}
+
+// summarizeBond 统计可用成员数并判断冗余是否降低
+// 聚合接口本身 down 或可用成员少于已加入的成员时视为冗余降低
+func summarizeBond(bond *models.BondMetrics) {
+	bond.Slaves = len(bond.Members)
+	bond.UpSlaves = 0
+	for _, member := range bond.Members {
+		if slaveUsable(*bond, member) {
+			bond.UpSlaves++
+		}
+	}
+	bond.ReducedRedundancy = bond.MIIStatus != "up" || bond.UpSlaves < bond.Slaves
+}
+
+// teamDevices 由 teamd 运行目录中的 pid 文件得到 team 接口列表
+// team 驱动没有类似 /proc/net/bonding 的状态文件，端口状态只能从 teamd 获取
+func teamDevices() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, dir := range teamdRunDirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.pid"))
+		for _, match := range matches {
+			name := strings.TrimSuffix(filepath.Base(match), ".pid")
+			if seen[name] {
+				continue
+			}
+			// 忽略 teamd 异常退出后残留的 pid 文件
+			if _, err := os.Stat(filepath.Join(sysClassNet, name)); err != nil {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// teamdState teamdctl state dump 输出中用到的字段
+type teamdState struct {
+	Setup struct {
+		RunnerName string `json:"runner_name"`
+	} `json:"setup"`
+	Runner struct {
+		ActivePort string `json:"active_port"`
+	} `json:"runner"`
+	Ports map[string]struct {
+		Link struct {
+			Duplex string `json:"duplex"`
+			Speed  int64  `json:"speed"`
+			Up     bool   `json:"up"`
+		} `json:"link"`
+		LinkWatches struct {
+			List map[string]struct {
+				DownCount uint64 `json:"down_count"`
+			} `json:"list"`
+			Up bool `json:"up"`
+		} `json:"link_watches"`
+		Runner struct {
+			Aggregator struct {
+				ID       int  `json:"id"`
+				Selected bool `json:"selected"`
+			} `json:"aggregator"`
+		} `json:"runner"`
+	} `json:"ports"`
+}
+
+// readTeam 通过 teamdctl <team> state dump 读取 team 接口状态
+func readTeam(name string) (models.BondMetrics, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), teamdctlTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "teamdctl", name, "state", "dump").Output()
+	if err != nil {
+		return models.BondMetrics{}, fmt.Errorf("teamdctl: %v", err)
+	}
+	return parseTeamState(name, output)
+}
+
+// parseTeamState 解析 teamd 状态 JSON
+func parseTeamState(name string, data []byte) (models.BondMetrics, error) {
+	var state teamdState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return models.BondMetrics{}, fmt.Errorf("teamdctl: %v", err)
+	}
+
+	bond := models.BondMetrics{
+		Name:        name,
+		Driver:      "team",
+		Mode:        state.Setup.RunnerName,
+		ActiveSlave: state.Runner.ActivePort,
+		MIIStatus:   "down",
+		Members:     []models.BondSlave{},
+	}
+	if operState, err := readSysfsString(filepath.Join(sysClassNet, name, "operstate")); err == nil && operState == "up" {
+		bond.MIIStatus = "up"
+	}
+
+	for port, info := range state.Ports {
+		member := models.BondSlave{
+			Interface: port,
+			MIIStatus: "down",
+			SpeedMbps: -1,
+			Duplex:    info.Link.Duplex,
+		}
+		// link_watches.up 综合了 ethtool/arp_ping 等链路检测结果
+		if info.LinkWatches.Up {
+			member.MIIStatus = "up"
+		}
+		if info.Link.Speed > 0 {
+			member.SpeedMbps = info.Link.Speed
+		}
+		for _, watch := range info.LinkWatches.List {
+			member.LinkFailures += watch.DownCount
+		}
+
+		switch state.Setup.RunnerName {
+		case "activebackup":
+			member.Active = member.MIIStatus == "up" && port == bond.ActiveSlave
+		case "lacp":
+			member.AggregatorID = info.Runner.Aggregator.ID
+			member.Active = member.MIIStatus == "up" && info.Runner.Aggregator.Selected
+		default:
+			member.Active = member.MIIStatus == "up"
+		}
+		bond.Members = append(bond.Members, member)
+	}
+	sort.Slice(bond.Members, func(i, j int) bool {
+		return bond.Members[i].Interface < bond.Members[j].Interface
+	})
+
+	summarizeBond(&bond)
+	return bond, nil
+}
@@ -0,0 +1,439 @@
+package collector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"host-monitor-agent/config"
+	"host-monitor-agent/models"
+	"host-monitor-agent/tailer"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// blockCommandTimeout 封禁命令的执行超时
+const blockCommandTimeout = 5 * time.Second
+
+// blockQueueSize 等待执行的封禁/解封命令上限
+const blockQueueSize = 256
+
+// blockJob 待执行的封禁或解封命令
+type blockJob struct {
+	ip      string
+	unban   bool
+	command []string
+	err     error
+}
+
+// hostileEntry 恶意来源及其封禁到期时间，持久化到状态目录
+type hostileEntry struct {
+	Info    models.HostileIP `json:"info"`
+	Expires time.Time        `json:"expires"`
+	Action  string           `json:"action"` // 封禁时的动作，解封使用同一动作
+	Issued  bool             `json:"issued"` // 封禁命令已提交（无论是否执行完成），到期时需要解封
+}
+
+// bruteForceDetector 按来源IP统计窗口内的 SSH 认证尝试，超过阈值判定为恶意并执行封禁动作
+type bruteForceDetector struct {
+	cfg        config.BruteForceConfig
+	stateDir   string // 封禁表保存目录，为空不持久化
+	stateErr   error
+	savedState []byte // 最近一次写入的内容，未变化时不重复写入
+	whitelist  []*net.IPNet
+	failures   *tailer.WindowCounter // 认证尝试次数，键为 "IP\x00用户"
+	hostile    map[string]*hostileEntry
+	detections uint64
+
+	// 命令在独立的 goroutine 中依次执行，避免阻塞采集；结果在下一次 evaluate 时处理
+	jobs    chan blockJob
+	mu      sync.Mutex
+	results []blockJob
+}
+
+// newBruteForceDetector 创建检测器，阈值为 0 时关闭检测
+// 从 stateDir 恢复上次运行的封禁表：到期的封禁在首次 evaluate 时解除，
+// 重启前已提交但可能尚未执行的封禁按剩余时长重新提交
+func newBruteForceDetector(cfg config.BruteForceConfig, stateDir string) *bruteForceDetector {
+	d := &bruteForceDetector{
+		cfg:       cfg,
+		stateDir:  stateDir,
+		whitelist: parseWhitelist(cfg.Whitelist),
+		failures:  tailer.NewWindowCounter(cfg.Window),
+		hostile:   make(map[string]*hostileEntry),
+	}
+	d.loadState()
+	return d
+}
+
+// submit 将命令放入执行队列，首次调用时启动执行 goroutine
+func (d *bruteForceDetector) submit(job blockJob) error {
+	if d.jobs == nil {
+		d.jobs = make(chan blockJob, blockQueueSize)
+		go d.runJobs()
+	}
+	select {
+	case d.jobs <- job:
+		return nil
+	default:
+		return fmt.Errorf("command queue full")
+	}
+}
+
+// runJobs 依次执行队列中的命令并暂存结果
+func (d *bruteForceDetector) runJobs() {
+	for job := range d.jobs {
+		job.err = d.run(job.command)
+		d.mu.Lock()
+		d.results = append(d.results, job)
+		d.mu.Unlock()
+	}
+}
+
+// applyResults 处理已完成的命令：更新封禁状态，失败时发出 brute_force_action_failed 事件
+func (d *bruteForceDetector) applyResults(events *eventBuffer) {
+	d.mu.Lock()
+	results := d.results
+	d.results = nil
+	d.mu.Unlock()
+
+	for _, job := range results {
+		if !job.unban {
+			// 到期解封时条目已删除，结果只用于事件
+			if entry := d.hostile[job.ip]; entry != nil {
+				entry.Info.Pending = false
+				if job.err != nil {
+					entry.Info.Error = job.err.Error()
+				} else {
+					entry.Info.Blocked = true
+				}
+			}
+		}
+		if job.err == nil {
+			continue
+		}
+		action := "ban"
+		if job.unban {
+			action = "unban"
+		}
+		events.emit("security", "brute_force_action_failed",
+			fmt.Sprintf("%s of %s failed: %v", action, job.ip, job.err),
+			map[string]string{
+				"ip":      job.ip,
+				"action":  action,
+				"command": strings.Join(job.command, " "),
+				"error":   job.err.Error(),
+			})
+	}
+}
+
+// enabled 是否开启检测
+func (d *bruteForceDetector) enabled() bool {
+	return d.cfg.Threshold > 0 && d.cfg.Window > 0
+}
+
+// record 记录一次认证事件，统计认证尝试次数
+// 仅密钥认证时不存在用户的尝试只有 "Invalid user" 一行，因此同时统计该行；
+// 与同一 sshd 进程的 "Failed ... for invalid user" 配对后只计一次（见 authAnalyzer.observe）
+func (d *bruteForceDetector) record(event authEvent) {
+	if !d.enabled() || (event.Kind != "ssh_failed" && event.Kind != "ssh_invalid_user") || event.Attempts == 0 {
+		return
+	}
+	// UseDNS 开启时来源可能是主机名，无法封禁
+	if net.ParseIP(event.IP) == nil {
+		return
+	}
+	// 按日志时间计入窗口：重启后 Tailer 从持久化位置补读积压的日志，
+	// 窗口之外的尝试不再计入，否则数小时前的尝试会在当前窗口内触发封禁
+	d.failures.AddAt(event.Time, event.IP+"\x00"+event.User, event.Attempts)
+}
+
+// evaluate 判定新的恶意来源并解除到期的封禁，变化通过 events 发出
+// 关闭检测后仍然解除恢复出的封禁
+func (d *bruteForceDetector) evaluate(events *eventBuffer) {
+	d.applyResults(events)
+	now := time.Now()
+
+	for ip, entry := range d.hostile {
+		if now.Before(entry.Expires) {
+			continue
+		}
+		delete(d.hostile, ip)
+		attrs := map[string]string{"ip": ip}
+		// 以是否提交过封禁为准：执行中的封禁命令排在解封命令之前，按顺序执行
+		if command := d.unbanCommand(entry.Action, ip); command != nil && entry.Issued {
+			attrs["command"] = strings.Join(command, " ")
+			if err := d.submit(blockJob{ip: ip, unban: true, command: command}); err != nil {
+				attrs["error"] = err.Error()
+			}
+		}
+		events.emit("security", "brute_force_expired",
+			fmt.Sprintf("ban on %s expired", ip), attrs)
+	}
+
+	if d.enabled() {
+		d.detect(now, events)
+	}
+	d.saveState()
+}
+
+// detect 判定窗口内尝试次数达到阈值的来源
+func (d *bruteForceDetector) detect(now time.Time, events *eventBuffer) {
+
+	failures := make(map[string]uint64)
+	keys := make(map[string][]string)
+	for key, n := range d.failures.CountByKey() {
+		ip, _, _ := strings.Cut(key, "\x00")
+		failures[ip] += n
+		keys[ip] = append(keys[ip], key)
+	}
+
+	for ip, n := range failures {
+		if n < uint64(d.cfg.Threshold) || d.hostile[ip] != nil || d.whitelisted(ip) {
+			continue
+		}
+
+		// 清空该来源的计数，封禁到期后需重新累计到阈值才会再次判定
+		users := len(keys[ip])
+		for _, key := range keys[ip] {
+			d.failures.Remove(key)
+		}
+
+		command := d.banCommand(d.cfg.Action, ip, d.cfg.BanDuration)
+		entry := &hostileEntry{
+			Action: d.cfg.Action,
+			Info: models.HostileIP{
+				IP:         ip,
+				Failures:   n,
+				Users:      users,
+				DetectedAt: now.UTC().Format("2006-01-02 15:04:05 MST"),
+				ExpiresAt:  now.Add(d.cfg.BanDuration).UTC().Format("2006-01-02 15:04:05 MST"),
+				Command:    strings.Join(command, " "),
+			},
+			Expires: now.Add(d.cfg.BanDuration),
+		}
+		switch {
+		case command == nil:
+			entry.Info.Error = "unsupported action: " + d.cfg.Action
+		case !d.cfg.DryRun:
+			if err := d.submit(blockJob{ip: ip, command: command}); err != nil {
+				entry.Info.Error = err.Error()
+			} else {
+				entry.Info.Pending = true
+				entry.Issued = true
+			}
+		}
+		d.hostile[ip] = entry
+		d.detections++
+
+		attrs := map[string]string{
+			"ip":       ip,
+			"failures": strconv.FormatUint(n, 10),
+			"users":    strconv.Itoa(users),
+			"command":  entry.Info.Command,
+			"dry_run":  strconv.FormatBool(d.cfg.DryRun),
+		}
+		if entry.Info.Error != "" {
+			attrs["error"] = entry.Info.Error
+		}
+		events.emit("security", "brute_force_detected",
+			fmt.Sprintf("%s flagged after %d failed login attempts in %v", ip, n, d.cfg.Window), attrs)
+	}
+}
+
+// fill 写入检测状态
+func (d *bruteForceDetector) fill(metrics *models.BruteForceMetrics) {
+	metrics.Enabled = d.enabled()
+	metrics.Action = d.cfg.Action
+	metrics.DryRun = d.cfg.DryRun
+	metrics.Threshold = d.cfg.Threshold
+	metrics.WindowSeconds = int(d.cfg.Window.Seconds())
+	metrics.BanSeconds = int(d.cfg.BanDuration.Seconds())
+	metrics.Detections = d.detections
+	if d.stateErr != nil {
+		metrics.Error = d.stateErr.Error()
+	}
+
+	metrics.HostileIPs = make([]models.HostileIP, 0, len(d.hostile))
+	for _, entry := range d.hostile {
+		metrics.HostileIPs = append(metrics.HostileIPs, entry.Info)
+	}
+	sort.Slice(metrics.HostileIPs, func(i, j int) bool {
+		a, b := metrics.HostileIPs[i], metrics.HostileIPs[j]
+		if a.DetectedAt != b.DetectedAt {
+			return a.DetectedAt < b.DetectedAt
+		}
+		return a.IP < b.IP
+	})
+}
+
+// banCommand 生成封禁时长为 duration 的封禁命令，ipset/nftables 由内核按 timeout 自动解除
+func (d *bruteForceDetector) banCommand(action, ip string, duration time.Duration) []string {
+	seconds := strconv.Itoa(int(duration.Seconds()))
+	ipv6 := net.ParseIP(ip).To4() == nil
+
+	switch action {
+	case "exec":
+		if d.cfg.ExecCommand == "" {
+			return nil
+		}
+		return []string{d.cfg.ExecCommand, "ban", ip, seconds}
+	case "ipset":
+		set := d.cfg.IPSetName
+		if ipv6 {
+			set += "6"
+		}
+		return []string{"ipset", "add", set, ip, "timeout", seconds, "-exist"}
+	case "nftables":
+		fields := strings.Fields(d.cfg.NFTSet)
+		if len(fields) != 3 {
+			return nil
+		}
+		if ipv6 {
+			fields[2] += "6"
+		}
+		return []string{"nft", "add", "element", fields[0], fields[1], fields[2],
+			"{ " + ip + " timeout " + seconds + "s }"}
+	}
+	return nil
+}
+
+// unbanCommand 生成解除封禁命令，只有 exec 动作需要由采集器主动解除；
+// ipset/nftables 的 timeout 保存在内核中，采集器停止期间同样到期解除
+func (d *bruteForceDetector) unbanCommand(action, ip string) []string {
+	if action != "exec" || d.cfg.ExecCommand == "" {
+		return nil
+	}
+	return []string{d.cfg.ExecCommand, "unban", ip}
+}
+
+// stateFile 封禁表文件路径
+func (d *bruteForceDetector) stateFile() string {
+	return filepath.Join(d.stateDir, "brute-force-state.json")
+}
+
+// loadState 恢复封禁表，重新提交重启前尚未确认执行完成的封禁
+func (d *bruteForceDetector) loadState() {
+	if d.stateDir == "" {
+		return
+	}
+	data, err := os.ReadFile(d.stateFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			d.stateErr = err
+		}
+		return
+	}
+	var entries []*hostileEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		d.stateErr = err
+		return
+	}
+
+	now := time.Now()
+	for _, entry := range entries {
+		d.hostile[entry.Info.IP] = entry
+		if !entry.Info.Pending || !now.Before(entry.Expires) {
+			continue
+		}
+		// 命令可能在重启前未执行，按剩余时长重新封禁
+		command := d.banCommand(entry.Action, entry.Info.IP, entry.Expires.Sub(now).Round(time.Second))
+		if command == nil {
+			continue
+		}
+		if err := d.submit(blockJob{ip: entry.Info.IP, command: command}); err != nil {
+			entry.Info.Pending = false
+			entry.Info.Error = err.Error()
+		}
+	}
+}
+
+// saveState 封禁表变化时写入磁盘
+func (d *bruteForceDetector) saveState() {
+	if d.stateDir == "" {
+		return
+	}
+	entries := make([]*hostileEntry, 0, len(d.hostile))
+	for _, entry := range d.hostile {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Info.IP < entries[j].Info.IP })
+	data, err := json.Marshal(entries)
+	if err != nil {
+		d.stateErr = err
+		return
+	}
+	if d.stateErr == nil && bytes.Equal(data, d.savedState) {
+		return
+	}
+	if d.stateErr = d.writeState(data); d.stateErr == nil {
+		d.savedState = data
+	}
+}
+
+// writeState 写入封禁表（先写临时文件再改名）
+func (d *bruteForceDetector) writeState(data []byte) error {
+	if err := os.MkdirAll(d.stateDir, 0755); err != nil {
+		return err
+	}
+	tmp := d.stateFile() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, d.stateFile())
+}
+
+// run 执行命令，失败时返回包含输出的错误
+func (d *bruteForceDetector) run(command []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), blockCommandTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, command[0], command[1:]...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%v: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// whitelisted 检查IP是否在白名单内
+func (d *bruteForceDetector) whitelisted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	for _, network := range d.whitelist {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWhitelist 解析白名单，单个地址视为 /32 或 /128，无法解析的条目忽略
+func parseWhitelist(entries []string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range entries {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				continue
+			}
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
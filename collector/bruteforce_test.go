@@ -0,0 +1,157 @@
+package collector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"host-monitor-agent/config"
+	"host-monitor-agent/models"
+)
+
+// newTestDetector 创建 dry-run 模式的检测器
+func newTestDetector(t *testing.T, threshold int) *bruteForceDetector {
+	t.Helper()
+	return newBruteForceDetector(config.BruteForceConfig{
+		Threshold:   threshold,
+		Window:      10 * time.Minute,
+		BanDuration: time.Hour,
+		Action:      "ipset",
+		DryRun:      true,
+		IPSetName:   "block",
+	}, "")
+}
+
+// feed 经认证分析器配对后交给检测器
+func feed(a *authAnalyzer, d *bruteForceDetector, lines ...string) {
+	for _, line := range lines {
+		if event, ok := a.observe(authLine(line)); ok {
+			d.record(event)
+		}
+	}
+}
+
+func TestBruteForceKeyOnlyInvalidUsers(t *testing.T) {
+	a := newAuthAnalyzer(10 * time.Minute)
+	d := newTestDetector(t, 3)
+
+	// 仅密钥认证的主机上扫描不存在的用户，没有 "Failed" 行
+	feed(a, d,
+		"sshd[11]: Invalid user admin from 198.51.100.7 port 40001",
+		"sshd[11]: Connection closed by invalid user admin 198.51.100.7 port 40001 [preauth]",
+		"sshd[12]: Invalid user oracle from 198.51.100.7 port 40002",
+		"sshd[13]: Invalid user test from 198.51.100.7 port 40003",
+	)
+	var events eventBuffer
+	d.evaluate(&events)
+	if d.hostile["198.51.100.7"] == nil {
+		t.Fatalf("key-only invalid-user scan not detected, events = %+v", events.DrainEvents())
+	}
+	if got := d.hostile["198.51.100.7"].Info.Failures; got != 3 {
+		t.Errorf("failures = %d, want 3", got)
+	}
+}
+
+func TestBruteForceCountsPairedInvalidUserOnce(t *testing.T) {
+	a := newAuthAnalyzer(10 * time.Minute)
+	d := newTestDetector(t, 3)
+
+	// 两次连接，每次 "Invalid user" 与 "Failed" 成对出现，共两次尝试
+	feed(a, d,
+		"sshd[21]: Invalid user guest from 203.0.113.9 port 50001",
+		"sshd[21]: Failed password for invalid user guest from 203.0.113.9 port 50001 ssh2",
+		"sshd[22]: Invalid user guest from 203.0.113.9 port 50002",
+		"sshd[22]: Failed password for invalid user guest from 203.0.113.9 port 50002 ssh2",
+	)
+	var events eventBuffer
+	d.evaluate(&events)
+	if d.hostile["203.0.113.9"] != nil {
+		t.Fatal("2 attempts flagged with threshold 3")
+	}
+
+	// 同一连接的第二次密码尝试是新的尝试
+	feed(a, d, "sshd[22]: Failed password for invalid user guest from 203.0.113.9 port 50002 ssh2")
+	d.evaluate(&events)
+	if d.hostile["203.0.113.9"] == nil {
+		t.Fatal("3 attempts not flagged with threshold 3")
+	}
+}
+
+func TestBruteForceBanTableSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	stateDir := filepath.Join(dir, "state")
+	logFile := filepath.Join(dir, "actions.log")
+	script := filepath.Join(dir, "block.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> "+logFile+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.BruteForceConfig{
+		Threshold:   2,
+		Window:      10 * time.Minute,
+		BanDuration: time.Hour,
+		Action:      "exec",
+		ExecCommand: script,
+	}
+
+	// 重启前：已执行的封禁、提交后尚未执行的封禁（一个已到期，一个未到期）
+	now := time.Now()
+	entries := []*hostileEntry{
+		{Info: models.HostileIP{IP: "192.0.2.1", Blocked: true}, Expires: now.Add(-time.Minute), Action: "exec", Issued: true},
+		{Info: models.HostileIP{IP: "192.0.2.2", Pending: true}, Expires: now.Add(-time.Minute), Action: "exec", Issued: true},
+		{Info: models.HostileIP{IP: "192.0.2.3", Pending: true}, Expires: now.Add(30 * time.Minute), Action: "exec", Issued: true},
+		// dry-run 判定的来源没有提交过封禁，到期无需解封
+		{Info: models.HostileIP{IP: "192.0.2.4"}, Expires: now.Add(-time.Minute), Action: "exec"},
+	}
+	data, _ := json.Marshal(entries)
+	os.MkdirAll(stateDir, 0755)
+	if err := os.WriteFile(filepath.Join(stateDir, "brute-force-state.json"), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	d := newBruteForceDetector(cfg, stateDir)
+	var events eventBuffer
+	d.evaluate(&events)
+	if len(d.hostile) != 1 || d.hostile["192.0.2.3"] == nil {
+		t.Fatalf("hostile after restart = %v", d.hostile)
+	}
+
+	// 等待后台命令执行完成，未到期的封禁按剩余时长（约 1800 秒）重新提交
+	want := []string{"ban 192.0.2.3", "unban 192.0.2.1", "unban 192.0.2.2"}
+	var got []string
+	deadline := time.Now().Add(5 * time.Second)
+	for len(got) < len(want) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		data, _ := os.ReadFile(logFile)
+		got = nil
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 3 {
+				if seconds, _ := strconv.Atoi(fields[2]); seconds < 1700 || seconds > 1800 {
+					t.Errorf("ban duration = %s, want about 1800", fields[2])
+				}
+			}
+			if len(fields) >= 2 {
+				got = append(got, fields[0]+" "+fields[1])
+			}
+		}
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+
+	// 封禁表已写回，不再包含到期的条目
+	d.evaluate(&events)
+	var saved []*hostileEntry
+	data, _ = os.ReadFile(filepath.Join(stateDir, "brute-force-state.json"))
+	if err := json.Unmarshal(data, &saved); err != nil || len(saved) != 1 || saved[0].Info.IP != "192.0.2.3" {
+		t.Errorf("saved state = %s", data)
+	}
+}
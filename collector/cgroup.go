@@ -0,0 +1,278 @@
+package collector
+
+import (
+	"bufio"
+	"host-monitor-agent/models"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cgroupRoot cgroup 文件系统挂载点
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupV1Unlimited v1 中 memory.limit_in_bytes 超过该值视为不限制
+const cgroupV1Unlimited = 1 << 62
+
+var (
+	// 容器 cgroup 目录名，如 docker-<id>.scope、cri-containerd-<id>.scope 或 /docker/<id>
+	cgroupContainerRe = regexp.MustCompile(`^(?:(docker|cri-containerd|crio|libpod)-)?([0-9a-f]{64})(?:\.scope)?$`)
+	// Pod UID，systemd 驱动下 "-" 被替换为 "_"
+	cgroupPodRe = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})(?:\.slice)?$`)
+)
+
+// cgroupRuntimes 目录名前缀与容器运行时的对应关系
+var cgroupRuntimes = map[string]string{
+	"docker":         "docker",
+	"cri-containerd": "containerd",
+	"crio":           "crio",
+	"libpod":         "podman",
+}
+
+// CgroupCollector cgroup 资源采集器
+type CgroupCollector struct {
+	// 上一次采集的 CPU 累计用量（秒），用于计算使用率
+	lastUsage map[string]float64
+	lastTime  time.Time
+}
+
+// Collect 遍历 cgroup 树，采集容器、Pod 与 slice 的资源使用
+func (c *CgroupCollector) Collect() (interface{}, error) {
+	metrics := models.CgroupMetrics{}
+
+	var walkRoot string
+	var read func(rel string, stats *models.CgroupStats)
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err == nil {
+		metrics.Version = 2
+		walkRoot = cgroupRoot
+		read = readCgroupV2
+	} else {
+		metrics.Version = 1
+		// v1 以 cpuacct 层级作为遍历基准，其余控制器按相同相对路径读取
+		for _, name := range []string{"cpuacct", "cpu,cpuacct", "memory"} {
+			if _, err := os.Stat(filepath.Join(cgroupRoot, name)); err == nil {
+				walkRoot = filepath.Join(cgroupRoot, name)
+				break
+			}
+		}
+		if walkRoot == "" {
+			return metrics, os.ErrNotExist
+		}
+		// cpuacct 通常是指向 cpu,cpuacct 的符号链接，WalkDir 不会进入符号链接根目录
+		if resolved, err := filepath.EvalSymlinks(walkRoot); err == nil {
+			walkRoot = resolved
+		}
+		read = readCgroupV1
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(c.lastTime).Seconds()
+	usage := make(map[string]float64)
+
+	err := filepath.WalkDir(walkRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || path == walkRoot {
+			return nil
+		}
+
+		rel := strings.TrimPrefix(path, walkRoot)
+		stats, ok := classifyCgroup(rel)
+		if !ok {
+			return nil
+		}
+
+		read(rel, &stats)
+
+		usage[rel] = stats.CPUUsageSeconds
+		if prev, ok := c.lastUsage[rel]; ok && elapsed > 0 && stats.CPUUsageSeconds >= prev {
+			stats.CPUUsagePercent = math.Round((stats.CPUUsageSeconds-prev)/elapsed*1000) / 10
+		}
+
+		metrics.Groups = append(metrics.Groups, stats)
+		return nil
+	})
+	if err != nil {
+		return metrics, err
+	}
+
+	c.lastUsage = usage
+	c.lastTime = now
+
+	return metrics, nil
+}
+
+// classifyCgroup 根据 cgroup 路径判断是否为容器、Pod 或 slice，并解析标识
+func classifyCgroup(rel string) (models.CgroupStats, bool) {
+	base := filepath.Base(rel)
+	stats := models.CgroupStats{Path: rel, Name: base}
+
+	// 容器所在的 Pod（路径中任意一级）
+	for dir := filepath.Dir(rel); dir != "/" && dir != "."; dir = filepath.Dir(dir) {
+		if m := cgroupPodRe.FindStringSubmatch(filepath.Base(dir)); m != nil {
+			stats.PodUID = strings.ReplaceAll(m[1], "_", "-")
+			break
+		}
+	}
+
+	switch {
+	case cgroupContainerRe.MatchString(base):
+		m := cgroupContainerRe.FindStringSubmatch(base)
+		stats.Type = "container"
+		stats.ContainerID = m[2]
+		stats.Name = m[2][:12]
+		stats.Runtime = cgroupRuntimes[m[1]]
+		if stats.Runtime == "" && filepath.Base(filepath.Dir(rel)) == "docker" {
+			stats.Runtime = "docker"
+		}
+	case cgroupPodRe.MatchString(base):
+		stats.Type = "pod"
+		stats.PodUID = strings.ReplaceAll(cgroupPodRe.FindStringSubmatch(base)[1], "_", "-")
+	case strings.HasSuffix(base, ".slice"):
+		stats.Type = "slice"
+	default:
+		return stats, false
+	}
+
+	if stats.PodUID != "" {
+		switch {
+		case strings.Contains(rel, "besteffort"):
+			stats.QoSClass = "besteffort"
+		case strings.Contains(rel, "burstable"):
+			stats.QoSClass = "burstable"
+		default:
+			stats.QoSClass = "guaranteed"
+		}
+	}
+
+	return stats, true
+}
+
+// readCgroupV2 读取 cgroup v2 统一层级的资源文件
+func readCgroupV2(rel string, stats *models.CgroupStats) {
+	dir := filepath.Join(cgroupRoot, rel)
+
+	if cpu, err := readKeyValueFile(filepath.Join(dir, "cpu.stat")); err == nil {
+		stats.CPUUsageSeconds = float64(cpu["usage_usec"]) / 1e6
+		stats.NrPeriods = cpu["nr_periods"]
+		stats.NrThrottled = cpu["nr_throttled"]
+		stats.ThrottledSeconds = float64(cpu["throttled_usec"]) / 1e6
+	}
+
+	stats.MemoryBytes, _ = readSysfsUint(filepath.Join(dir, "memory.current"))
+	// "max" 表示不限制，解析失败即为 0
+	stats.MemoryMaxBytes, _ = readSysfsUint(filepath.Join(dir, "memory.max"))
+	if events, err := readKeyValueFile(filepath.Join(dir, "memory.events")); err == nil {
+		stats.MemoryOOM = events["oom"]
+		stats.MemoryOOMKill = events["oom_kill"]
+	}
+
+	// io.stat: "8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=0 dios=0"
+	if data, err := os.ReadFile(filepath.Join(dir, "io.stat")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			for _, field := range strings.Fields(line) {
+				key, value, ok := strings.Cut(field, "=")
+				if !ok {
+					continue
+				}
+				v, err := strconv.ParseUint(value, 10, 64)
+				if err != nil {
+					continue
+				}
+				switch key {
+				case "rbytes":
+					stats.IOReadBytes += v
+				case "wbytes":
+					stats.IOWriteBytes += v
+				case "rios":
+					stats.IOReadOps += v
+				case "wios":
+					stats.IOWriteOps += v
+				}
+			}
+		}
+	}
+
+	stats.PidsCurrent, _ = readSysfsUint(filepath.Join(dir, "pids.current"))
+	stats.PidsMax, _ = readSysfsUint(filepath.Join(dir, "pids.max"))
+}
+
+// readCgroupV1 按控制器分别读取 cgroup v1 资源文件
+func readCgroupV1(rel string, stats *models.CgroupStats) {
+	controller := func(names ...string) string {
+		for _, name := range names {
+			dir := filepath.Join(cgroupRoot, name, rel)
+			if _, err := os.Stat(dir); err == nil {
+				return dir
+			}
+		}
+		return ""
+	}
+
+	if dir := controller("cpuacct", "cpu,cpuacct"); dir != "" {
+		if ns, err := readSysfsUint(filepath.Join(dir, "cpuacct.usage")); err == nil {
+			stats.CPUUsageSeconds = float64(ns) / 1e9
+		}
+	}
+	if dir := controller("cpu", "cpu,cpuacct"); dir != "" {
+		if cpu, err := readKeyValueFile(filepath.Join(dir, "cpu.stat")); err == nil {
+			stats.NrPeriods = cpu["nr_periods"]
+			stats.NrThrottled = cpu["nr_throttled"]
+			stats.ThrottledSeconds = float64(cpu["throttled_time"]) / 1e9
+		}
+	}
+
+	if dir := controller("memory"); dir != "" {
+		stats.MemoryBytes, _ = readSysfsUint(filepath.Join(dir, "memory.usage_in_bytes"))
+		if limit, err := readSysfsUint(filepath.Join(dir, "memory.limit_in_bytes")); err == nil && limit < cgroupV1Unlimited {
+			stats.MemoryMaxBytes = limit
+		}
+		// v1 只提供 oom_kill 次数（4.13+），没有与 v2 memory.events oom 对应的计数
+		if oom, err := readKeyValueFile(filepath.Join(dir, "memory.oom_control")); err == nil {
+			stats.MemoryOOMKill = oom["oom_kill"]
+		}
+	}
+
+	if dir := controller("blkio"); dir != "" {
+		stats.IOReadBytes, stats.IOWriteBytes = readBlkioFile(filepath.Join(dir, "blkio.throttle.io_service_bytes"))
+		stats.IOReadOps, stats.IOWriteOps = readBlkioFile(filepath.Join(dir, "blkio.throttle.io_serviced"))
+	}
+
+	if dir := controller("pids"); dir != "" {
+		stats.PidsCurrent, _ = readSysfsUint(filepath.Join(dir, "pids.current"))
+		stats.PidsMax, _ = readSysfsUint(filepath.Join(dir, "pids.max"))
+	}
+}
+
+// readBlkioFile 汇总 v1 blkio 文件中各设备的 Read/Write 行
+// 行格式: "8:0 Read 4096"
+func readBlkioFile(path string) (read, write uint64) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[1] {
+		case "Read":
+			read += v
+		case "Write":
+			write += v
+		}
+	}
+
+	return read, write
+}
@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"host-monitor-agent/models"
+	"math"
+	"time"
+)
+
+// sntpQueryInterval 同一服务器两次 SNTP 查询的最小间隔，避免触发服务器限速
+const sntpQueryInterval = time.Minute
+
+// kernelClock adjtimex 返回的内核时钟状态
+type kernelClock struct {
+	Synced          bool
+	OffsetSeconds   float64
+	FrequencyPPM    float64
+	MaxErrorSeconds float64
+	EstErrorSeconds float64
+}
+
+// ClockCollector 时钟同步采集器
+type ClockCollector struct {
+	Servers []string // SNTP 服务器，为空时只读取内核状态
+
+	// 最近一次 SNTP 查询结果
+	lastNTP   []models.NTPOffset
+	lastQuery time.Time
+}
+
+// Collect 采集内核时钟同步状态，并按需查询 SNTP 服务器测量偏差
+func (c *ClockCollector) Collect() (interface{}, error) {
+	metrics := models.ClockMetrics{}
+
+	if clock, err := readKernelClock(); err == nil {
+		metrics.Available = true
+		metrics.Synced = clock.Synced
+		metrics.OffsetSeconds = roundSeconds(clock.OffsetSeconds)
+		metrics.FrequencyPPM = math.Round(clock.FrequencyPPM*1000) / 1000
+		metrics.MaxErrorSeconds = roundSeconds(clock.MaxErrorSeconds)
+		metrics.EstErrorSeconds = roundSeconds(clock.EstErrorSeconds)
+	}
+
+	if len(c.Servers) > 0 {
+		if c.lastNTP == nil || time.Since(c.lastQuery) >= sntpQueryInterval {
+			c.lastNTP = queryNTPServers(c.Servers)
+			c.lastQuery = time.Now()
+		}
+		metrics.NTP = c.lastNTP
+	}
+
+	return metrics, nil
+}
+
+// queryNTPServers 依次查询所有服务器
+func queryNTPServers(servers []string) []models.NTPOffset {
+	results := make([]models.NTPOffset, 0, len(servers))
+	for _, server := range servers {
+		offset := models.NTPOffset{
+			Server:    server,
+			QueriedAt: time.Now().UTC().Format("2006-01-02 15:04:05 MST"),
+		}
+
+		result, err := querySNTP(server, sntpTimeout)
+		if err != nil {
+			offset.Error = err.Error()
+		} else {
+			offset.OffsetSeconds = roundSeconds(result.Offset.Seconds())
+			offset.RTTSeconds = roundSeconds(result.RTT.Seconds())
+			offset.Stratum = result.Stratum
+		}
+
+		results = append(results, offset)
+	}
+	return results
+}
+
+// roundSeconds 秒数保留到微秒
+func roundSeconds(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
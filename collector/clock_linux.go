@@ -0,0 +1,36 @@
+//go:build linux
+
+package collector
+
+import "syscall"
+
+const (
+	// staUnsync 时钟未同步（include/uapi/linux/timex.h）
+	staUnsync = 0x0040
+	// staNano offset 单位为纳秒而非微秒
+	staNano = 0x2000
+	// timeError 时钟状态异常
+	timeError = 5
+)
+
+// readKernelClock 通过只读 adjtimex 获取内核时钟同步状态
+func readKernelClock() (kernelClock, error) {
+	var tx syscall.Timex
+	state, err := syscall.Adjtimex(&tx)
+	if err != nil {
+		return kernelClock{}, err
+	}
+
+	offsetUnit := 1e-6
+	if tx.Status&staNano != 0 {
+		offsetUnit = 1e-9
+	}
+
+	return kernelClock{
+		Synced:          state != timeError && tx.Status&staUnsync == 0,
+		OffsetSeconds:   float64(tx.Offset) * offsetUnit,
+		FrequencyPPM:    float64(tx.Freq) / 65536,
+		MaxErrorSeconds: float64(tx.Maxerror) * 1e-6,
+		EstErrorSeconds: float64(tx.Esterror) * 1e-6,
+	}, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package collector
+
+import "errors"
+
+// readKernelClock 非 Linux 平台不支持 adjtimex
+func readKernelClock() (kernelClock, error) {
+	return kernelClock{}, errors.New("adjtimex is not supported on this platform")
+}
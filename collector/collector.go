@@ -1,6 +1,9 @@
 package collector
 
-import "host-monitor-agent/models"
+import (
+	"host-monitor-agent/config"
+	"host-monitor-agent/models"
+)
 
 // Collector 指标采集器接口
 type Collector interface {
@@ -9,29 +12,78 @@ type Collector interface {
 
 // MetricsCollector 所有指标采集器的管理器
 type MetricsCollector struct {
-	hostInfoCollector Collector
-	cpuCollector      Collector
-	memoryCollector   Collector
-	diskCollector     Collector
-	loadCollector     Collector
-	tcpCollector      Collector
-	fdCollector       Collector
-	networkCollector  Collector
-	securityCollector Collector
+	hostInfoCollector  Collector
+	clockCollector     Collector
+	cpuCollector       Collector
+	cpuFreqCollector   Collector
+	memoryCollector    Collector
+	diskCollector      Collector
+	nfsCollector       Collector
+	storageCollector   Collector
+	loadCollector      Collector
+	kernelCollector    Collector
+	kernelLogCollector Collector
+	sensorCollector    Collector
+	tcpCollector       Collector
+	netStatCollector   Collector
+	listenerCollector  Collector
+	sockStatCollector  Collector
+	conntrackCollector Collector
+	fdCollector        Collector
+	networkCollector   Collector
+	bondCollector      Collector
+	cgroupCollector    Collector
+	dockerCollector    Collector
+	securityCollector  Collector
+	sshdCollector      Collector
+	accountCollector   Collector
+	loginCollector     Collector
+	integrityCollector Collector
+	benchmarkCollector Collector
+	packageCollector   Collector
 }
 
 // NewMetricsCollector 创建指标采集器管理器
-func NewMetricsCollector() *MetricsCollector {
+func NewMetricsCollector(cfg config.CollectorConfig) *MetricsCollector {
 	return &MetricsCollector{
-		hostInfoCollector: &HostInfoCollector{},
-		cpuCollector:      &CPUCollector{},
-		memoryCollector:   &MemoryCollector{},
-		diskCollector:     &DiskCollector{},
-		loadCollector:     &LoadCollector{},
-		tcpCollector:      &TCPCollector{},
-		fdCollector:       &FDCollector{},
-		networkCollector:  &NetworkCollector{},
-		securityCollector: &SecurityCollector{},
+		hostInfoCollector:  &HostInfoCollector{},
+		clockCollector:     &ClockCollector{Servers: cfg.NTPServers},
+		cpuCollector:       &CPUCollector{},
+		cpuFreqCollector:   &CPUFreqCollector{},
+		memoryCollector:    &MemoryCollector{},
+		diskCollector:      &DiskCollector{},
+		nfsCollector:       &NFSCollector{},
+		storageCollector:   &StorageCollector{},
+		loadCollector:      &LoadCollector{},
+		kernelCollector:    &KernelCollector{},
+		kernelLogCollector: &KernelLogCollector{StateDir: cfg.StateDir},
+		sensorCollector:    &SensorCollector{},
+		tcpCollector:       &TCPCollector{},
+		netStatCollector:   &NetStatCollector{},
+		listenerCollector:  &ListenerCollector{},
+		sockStatCollector:  &SockStatCollector{},
+		conntrackCollector: &ConntrackCollector{},
+		fdCollector:        &FDCollector{},
+		networkCollector:   &NetworkCollector{},
+		bondCollector:      &BondCollector{},
+		cgroupCollector:    &CgroupCollector{},
+		dockerCollector:    &DockerCollector{},
+		securityCollector:  &SecurityCollector{StateDir: cfg.StateDir, BruteForce: cfg.BruteForce},
+		sshdCollector:      &SSHDConfigCollector{},
+		accountCollector:   &AccountCollector{},
+		loginCollector:     &LoginCollector{},
+		integrityCollector: &IntegrityCollector{
+			Paths:    cfg.Integrity.Paths,
+			Interval: cfg.Integrity.Interval,
+			MaxFiles: cfg.Integrity.MaxFiles,
+			StateDir: cfg.StateDir,
+		},
+		benchmarkCollector: &BenchmarkCollector{
+			RulesPath: cfg.Benchmark.RulesPath,
+			Interval:  cfg.Benchmark.Interval,
+			StateDir:  cfg.StateDir,
+		},
+		packageCollector: &PackageCollector{},
 	}
 }
 
@@ -50,11 +102,21 @@ func (mc *MetricsCollector) CollectAll() (*models.HostMetrics, error) {
 		metrics.Uptime = info.Uptime
 	}
 
+	// 采集时钟同步状态
+	if clock, err := mc.clockCollector.Collect(); err == nil {
+		metrics.Clock = clock.(models.ClockMetrics)
+	}
+
 	// 采集CPU
 	if cpu, err := mc.cpuCollector.Collect(); err == nil {
 		metrics.CPU = cpu.(models.CPUMetrics)
 	}
 
+	// 采集CPU频率
+	if cpuFreq, err := mc.cpuFreqCollector.Collect(); err == nil {
+		metrics.CPUFreq = cpuFreq.(models.CPUFreqMetrics)
+	}
+
 	// 采集内存
 	if mem, err := mc.memoryCollector.Collect(); err == nil {
 		metrics.Memory = mem.(models.MemoryMetrics)
@@ -65,16 +127,61 @@ func (mc *MetricsCollector) CollectAll() (*models.HostMetrics, error) {
 		metrics.Disk = disk.([]models.DiskMetrics)
 	}
 
+	// 采集NFS客户端统计
+	if nfs, err := mc.nfsCollector.Collect(); err == nil {
+		metrics.NFS = nfs.([]models.NFSMountMetrics)
+	}
+
+	// 采集软RAID、LVM与ZFS状态
+	if storage, err := mc.storageCollector.Collect(); err == nil {
+		metrics.Storage = storage.(models.StorageMetrics)
+	}
+
 	// 采集负载
 	if load, err := mc.loadCollector.Collect(); err == nil {
 		metrics.Load = load.(models.LoadMetrics)
 	}
 
+	// 采集内核活动计数器
+	if kernel, err := mc.kernelCollector.Collect(); err == nil {
+		metrics.Kernel = kernel.(models.KernelMetrics)
+	}
+
+	// 采集内核日志异常事件
+	if kernelLog, err := mc.kernelLogCollector.Collect(); err == nil {
+		metrics.KernelLog = kernelLog.(models.KernelLogMetrics)
+	}
+
+	// 采集硬件传感器
+	if sensors, err := mc.sensorCollector.Collect(); err == nil {
+		metrics.Sensors = sensors.(models.SensorMetrics)
+	}
+
 	// 采集TCP连接
 	if tcp, err := mc.tcpCollector.Collect(); err == nil {
 		metrics.TCP = tcp.(models.TCPMetrics)
 	}
 
+	// 采集协议计数器
+	if netStat, err := mc.netStatCollector.Collect(); err == nil {
+		metrics.NetStat = netStat.(models.NetStatMetrics)
+	}
+
+	// 采集监听套接字
+	if listeners, err := mc.listenerCollector.Collect(); err == nil {
+		metrics.Listeners = listeners.(models.ListenerMetrics)
+	}
+
+	// 采集套接字汇总
+	if sockStat, err := mc.sockStatCollector.Collect(); err == nil {
+		metrics.SockStat = sockStat.(models.SockStatMetrics)
+	}
+
+	// 采集连接跟踪表
+	if conntrack, err := mc.conntrackCollector.Collect(); err == nil {
+		metrics.Conntrack = conntrack.(models.ConntrackMetrics)
+	}
+
 	// 采集文件描述符
 	if fd, err := mc.fdCollector.Collect(); err == nil {
 		metrics.FileDescriptor = fd.(models.FDMetrics)
@@ -85,10 +192,78 @@ func (mc *MetricsCollector) CollectAll() (*models.HostMetrics, error) {
 		metrics.Network = network.([]models.NetworkMetrics)
 	}
 
+	// 采集bonding/team聚合接口
+	if bonds, err := mc.bondCollector.Collect(); err == nil {
+		metrics.Bonds = bonds.([]models.BondMetrics)
+	}
+
+	// 采集cgroup资源
+	if cgroups, err := mc.cgroupCollector.Collect(); err == nil {
+		metrics.Cgroups = cgroups.(models.CgroupMetrics)
+	}
+
+	// 采集Docker容器
+	if docker, err := mc.dockerCollector.Collect(); err == nil {
+		metrics.Docker = docker.(models.DockerMetrics)
+	}
+
 	// 采集安全指标
 	if security, err := mc.securityCollector.Collect(); err == nil {
 		metrics.Security = security.(models.SecurityMetrics)
 	}
 
+	// 采集sshd配置加固检查
+	if sshd, err := mc.sshdCollector.Collect(); err == nil {
+		metrics.Security.SSHDConfig = sshd.(models.SSHDConfigMetrics)
+	}
+
+	// 采集账户与特权审计
+	if accounts, err := mc.accountCollector.Collect(); err == nil {
+		metrics.Accounts = accounts.(models.AccountMetrics)
+	}
+
+	// 采集登录会话与登录历史
+	if logins, err := mc.loginCollector.Collect(); err == nil {
+		metrics.Logins = logins.(models.LoginMetrics)
+	}
+
+	// 采集文件完整性
+	if integrity, err := mc.integrityCollector.Collect(); err == nil {
+		metrics.Integrity = integrity.(models.IntegrityMetrics)
+	}
+
+	// 采集合规基线检查
+	if compliance, err := mc.benchmarkCollector.Collect(); err == nil {
+		metrics.Compliance = compliance.(models.ComplianceMetrics)
+	}
+
+	// 采集已安装软件包
+	if packages, err := mc.packageCollector.Collect(); err == nil {
+		metrics.Packages = packages.(models.PackageMetrics)
+	}
+
+	// 收集本轮采集产生的事件
+	metrics.Events = mc.drainEvents()
+
 	return metrics, nil
-}
\ No newline at end of file
+}
+
+// drainEvents 取走所有事件源采集器暂存的事件
+func (mc *MetricsCollector) drainEvents() []models.Event {
+	events := []models.Event{}
+	for _, c := range []Collector{
+		mc.kernelLogCollector,
+		mc.listenerCollector,
+		mc.bondCollector,
+		mc.securityCollector,
+		mc.accountCollector,
+		mc.integrityCollector,
+		mc.benchmarkCollector,
+		mc.packageCollector,
+	} {
+		if source, ok := c.(EventSource); ok {
+			events = append(events, source.DrainEvents()...)
+		}
+	}
+	return events
+}
@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"bufio"
+	"host-monitor-agent/models"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// conntrackBreakdownLimit 连接数超过该值时不再逐条解析 /proc/net/nf_conntrack
+// 大表逐条读取开销与表大小成正比，且读取期间会持有表锁
+const conntrackBreakdownLimit = 65536
+
+// ConntrackCollector 连接跟踪表采集器
+type ConntrackCollector struct{}
+
+// Collect 采集 nf_conntrack 使用量与统计
+func (c *ConntrackCollector) Collect() (interface{}, error) {
+	metrics := models.ConntrackMetrics{}
+
+	count, err := readSysfsUint("/proc/sys/net/netfilter/nf_conntrack_count")
+	if err != nil {
+		// 未加载 nf_conntrack 模块
+		return metrics, nil
+	}
+	metrics.Available = true
+	metrics.Count = count
+
+	if max, err := readSysfsUint("/proc/sys/net/netfilter/nf_conntrack_max"); err == nil {
+		metrics.Max = max
+		if max > 0 {
+			metrics.UsagePercent = math.Round(float64(count)/float64(max)*1000) / 10
+		}
+	}
+
+	if perCPU, err := parseConntrackStats("/proc/net/stat/nf_conntrack"); err == nil {
+		metrics.PerCPU = perCPU
+		metrics.Stats.CPU = -1
+		for _, stats := range perCPU {
+			metrics.Stats.Found += stats.Found
+			metrics.Stats.Invalid += stats.Invalid
+			metrics.Stats.Insert += stats.Insert
+			metrics.Stats.InsertFailed += stats.InsertFailed
+			metrics.Stats.Drop += stats.Drop
+			metrics.Stats.EarlyDrop += stats.EarlyDrop
+			metrics.Stats.SearchRestart += stats.SearchRestart
+		}
+	}
+
+	if count <= conntrackBreakdownLimit {
+		metrics.ByProtocol, metrics.ByState = conntrackBreakdown("/proc/net/nf_conntrack")
+	}
+
+	return metrics, nil
+}
+
+// parseConntrackStats 解析按CPU输出的十六进制统计表
+// 列随内核版本变化，按表头名称取值
+func parseConntrackStats(path string) ([]models.ConntrackStats, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return nil, scanner.Err()
+	}
+	header := strings.Fields(scanner.Text())
+
+	var result []models.ConntrackStats
+	for cpu := 0; scanner.Scan(); cpu++ {
+		fields := strings.Fields(scanner.Text())
+		values := make(map[string]uint64, len(header))
+		for i := 0; i < len(header) && i < len(fields); i++ {
+			if v, err := strconv.ParseUint(fields[i], 16, 64); err == nil {
+				values[header[i]] = v
+			}
+		}
+
+		result = append(result, models.ConntrackStats{
+			CPU:           cpu,
+			Found:         values["found"],
+			Invalid:       values["invalid"],
+			Insert:        values["insert"],
+			InsertFailed:  values["insert_failed"],
+			Drop:          values["drop"],
+			EarlyDrop:     values["early_drop"],
+			SearchRestart: values["search_restart"],
+		})
+	}
+
+	return result, scanner.Err()
+}
+
+// conntrackBreakdown 按协议与 TCP 状态统计连接跟踪条目
+// 行格式: "ipv4 2 tcp 6 431999 ESTABLISHED src=..."，无状态协议缺少状态列
+func conntrackBreakdown(path string) (map[string]uint64, map[string]uint64) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil
+	}
+	defer file.Close()
+
+	byProtocol := make(map[string]uint64)
+	byState := make(map[string]uint64)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 {
+			continue
+		}
+
+		byProtocol[fields[2]]++
+		if fields[2] == "tcp" && !strings.Contains(fields[5], "=") {
+			byState[fields[5]]++
+		}
+	}
+
+	return byProtocol, byState
+}
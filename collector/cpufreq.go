@@ -0,0 +1,158 @@
+package collector
+
+import (
+	"host-monitor-agent/models"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sysCPU CPU 设备 sysfs 目录
+const sysCPU = "/sys/devices/system/cpu"
+
+// CPUFreqCollector CPU频率与调速器采集器
+type CPUFreqCollector struct {
+	// 上一次采集的各 C-state 累计驻留时间（微秒），用于计算区间占比
+	lastCStateTime map[string]uint64
+	lastTime       time.Time
+}
+
+// Collect 采集每个核心的频率、调速器以及 C-state 驻留分布
+func (c *CPUFreqCollector) Collect() (interface{}, error) {
+	metrics := models.CPUFreqMetrics{
+		GovernorCounts: map[string]int{},
+	}
+
+	cpuDirs, _ := filepath.Glob(filepath.Join(sysCPU, "cpu[0-9]*"))
+	sort.Slice(cpuDirs, func(i, j int) bool {
+		return cpuIndex(cpuDirs[i]) < cpuIndex(cpuDirs[j])
+	})
+
+	var totalMHz float64
+	for _, cpuDir := range cpuDirs {
+		freqDir := filepath.Join(cpuDir, "cpufreq")
+		cur, err := readSysfsUint(filepath.Join(freqDir, "scaling_cur_freq"))
+		if err != nil {
+			continue
+		}
+
+		core := models.CPUCoreFreq{
+			CPU:        cpuIndex(cpuDir),
+			CurrentMHz: khzToMHz(cur),
+		}
+		if v, err := readSysfsUint(filepath.Join(freqDir, "scaling_min_freq")); err == nil {
+			core.MinMHz = khzToMHz(v)
+		}
+		if v, err := readSysfsUint(filepath.Join(freqDir, "scaling_max_freq")); err == nil {
+			core.MaxMHz = khzToMHz(v)
+		}
+		if governor, err := readSysfsString(filepath.Join(freqDir, "scaling_governor")); err == nil {
+			core.Governor = governor
+			metrics.GovernorCounts[governor]++
+		}
+		if metrics.Driver == "" {
+			metrics.Driver, _ = readSysfsString(filepath.Join(freqDir, "scaling_driver"))
+		}
+
+		if len(metrics.Cores) == 0 || core.CurrentMHz < metrics.MinMHz {
+			metrics.MinMHz = core.CurrentMHz
+		}
+		if core.CurrentMHz > metrics.MaxMHz {
+			metrics.MaxMHz = core.CurrentMHz
+		}
+		totalMHz += core.CurrentMHz
+
+		metrics.Cores = append(metrics.Cores, core)
+	}
+
+	if len(metrics.Cores) > 0 {
+		metrics.Available = true
+		metrics.AvgMHz = math.Round(totalMHz/float64(len(metrics.Cores))*10) / 10
+	}
+	switch len(metrics.GovernorCounts) {
+	case 0:
+	case 1:
+		for governor := range metrics.GovernorCounts {
+			metrics.Governor = governor
+		}
+	default:
+		metrics.Governor = "mixed"
+	}
+
+	metrics.IdleDriver, _ = readSysfsString(filepath.Join(sysCPU, "cpuidle", "current_driver"))
+	metrics.CStates = c.collectCStates(cpuDirs)
+
+	return metrics, nil
+}
+
+// collectCStates 汇总所有CPU的 cpuidle/state*，按状态名合并
+// 区间占比 = 驻留时间增量 / (采集间隔 × CPU数)
+func (c *CPUFreqCollector) collectCStates(cpuDirs []string) []models.CStateResidency {
+	now := time.Now()
+	elapsed := now.Sub(c.lastTime).Seconds()
+
+	totals := make(map[string]*models.CStateResidency)
+	timeUsec := make(map[string]uint64)
+	var order []string
+	cpuCount := 0
+
+	for _, cpuDir := range cpuDirs {
+		stateDirs, _ := filepath.Glob(filepath.Join(cpuDir, "cpuidle", "state[0-9]*"))
+		if len(stateDirs) == 0 {
+			continue
+		}
+		cpuCount++
+
+		sort.Strings(stateDirs)
+		for _, stateDir := range stateDirs {
+			name, err := readSysfsString(filepath.Join(stateDir, "name"))
+			if err != nil {
+				continue
+			}
+			usage, _ := readSysfsUint(filepath.Join(stateDir, "usage"))
+			usec, _ := readSysfsUint(filepath.Join(stateDir, "time"))
+
+			state, ok := totals[name]
+			if !ok {
+				state = &models.CStateResidency{Name: name}
+				totals[name] = state
+				order = append(order, name)
+			}
+			state.Usage += usage
+			timeUsec[name] += usec
+		}
+	}
+
+	var result []models.CStateResidency
+	for _, name := range order {
+		state := totals[name]
+		state.TimeSeconds = math.Round(float64(timeUsec[name])/1e5) / 10
+		if prev, ok := c.lastCStateTime[name]; ok && elapsed > 0 && cpuCount > 0 {
+			rate := counterRate(timeUsec[name], prev, elapsed) / 1e6 / float64(cpuCount)
+			state.ResidencyPercent = math.Round(rate*1000) / 10
+		}
+		result = append(result, *state)
+	}
+
+	c.lastCStateTime = timeUsec
+	c.lastTime = now
+
+	return result
+}
+
+// cpuIndex 从 cpuN 目录名解析CPU编号
+func cpuIndex(dir string) int {
+	index, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "cpu"))
+	if err != nil {
+		return -1
+	}
+	return index
+}
+
+// khzToMHz kHz 转换为 MHz
+func khzToMHz(khz uint64) float64 {
+	return math.Round(float64(khz)/100) / 10
+}
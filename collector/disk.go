@@ -1,14 +1,54 @@
 package collector
 
 import (
+	"errors"
 	"host-monitor-agent/models"
 	"math"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/disk"
 )
 
+// diskStatTimeout 单个挂载点 statfs 的超时时间，超时视为挂载失效（stale）
+const diskStatTimeout = 5 * time.Second
+
+// diskStatWorkers 并发执行 statfs 的数量上限
+const diskStatWorkers = 8
+
+// errMountStale statfs 超时或上一次调用仍未返回
+var errMountStale = errors.New("statfs timed out")
+
+// networkFSTypes 网络文件系统类型，服务端无响应时 statfs 可能永久阻塞
+// 这些类型在 /proc/filesystems 中标记为 nodev，disk.Partitions(false) 不会返回
+var networkFSTypes = map[string]bool{
+	"nfs":            true,
+	"nfs4":           true,
+	"cifs":           true,
+	"smb3":           true,
+	"smbfs":          true,
+	"ceph":           true,
+	"glusterfs":      true,
+	"fuse.glusterfs": true,
+	"fuse.sshfs":     true,
+	"fuse.cephfs":    true,
+	"lustre":         true,
+	"9p":             true,
+}
+
 // DiskCollector 磁盘指标采集器
-type DiskCollector struct{}
+// statfs 在独立的 goroutine 中执行并设置超时，失效的 NFS/CIFS 挂载不会阻塞整个采集
+type DiskCollector struct {
+	mu      sync.Mutex
+	pending map[string]bool // statfs 仍未返回的挂载点
+}
+
+// diskStatResult statfs 结果
+type diskStatResult struct {
+	usage *disk.UsageStat
+	err   error
+}
 
 // Collect 采集磁盘指标
 func (d *DiskCollector) Collect() (interface{}, error) {
@@ -16,15 +56,54 @@ func (d *DiskCollector) Collect() (interface{}, error) {
 	if err != nil {
 		return []models.DiskMetrics{}, err
 	}
+	if all, err := disk.Partitions(true); err == nil {
+		for _, partition := range all {
+			if networkFSTypes[partition.Fstype] {
+				partitions = append(partitions, partition)
+			}
+		}
+	}
 
-	var diskMetrics []models.DiskMetrics
+	// 过滤掉不需要监控的分区
+	var candidates []disk.PartitionStat
+	seen := make(map[string]bool)
 	for _, partition := range partitions {
-		// 过滤掉不需要监控的分区
-		if shouldSkipPartition(partition.Mountpoint) {
+		if shouldSkipPartition(partition.Mountpoint) || seen[partition.Mountpoint] {
 			continue
 		}
+		seen[partition.Mountpoint] = true
+		candidates = append(candidates, partition)
+	}
 
-		usage, err := disk.Usage(partition.Mountpoint)
+	// 并发 statfs，单次采集耗时不超过 diskStatTimeout 的若干倍
+	results := make([]diskStatResult, len(candidates))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, diskStatWorkers)
+	for i, partition := range candidates {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, mountPoint string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			usage, err := d.usage(mountPoint)
+			results[i] = diskStatResult{usage: usage, err: err}
+		}(i, partition.Mountpoint)
+	}
+	wg.Wait()
+
+	var diskMetrics []models.DiskMetrics
+	for i, partition := range candidates {
+		usage, err := results[i].usage, results[i].err
+		if err == errMountStale {
+			diskMetrics = append(diskMetrics, models.DiskMetrics{
+				MountPoint: partition.Mountpoint,
+				Device:     partition.Device,
+				FSType:     partition.Fstype,
+				Server:     mountServer(partition.Fstype, partition.Device),
+				Stale:      true,
+			})
+			continue
+		}
 		if err != nil {
 			continue
 		}
@@ -40,6 +119,9 @@ func (d *DiskCollector) Collect() (interface{}, error) {
 
 		diskMetrics = append(diskMetrics, models.DiskMetrics{
 			MountPoint:   partition.Mountpoint,
+			Device:       partition.Device,
+			FSType:       partition.Fstype,
+			Server:       mountServer(partition.Fstype, partition.Device),
 			Total:        totalGB,
 			Used:         usedGB,
 			UsagePercent: math.Round(usage.UsedPercent*10) / 10,
@@ -49,6 +131,61 @@ func (d *DiskCollector) Collect() (interface{}, error) {
 	return diskMetrics, nil
 }
 
+// usage 带超时的 statfs
+// 阻塞在内核中的 statfs 无法取消，同一挂载点上一次调用未返回前不再发起新的调用，
+// 因此每个失效挂载点最多占用一个 goroutine
+func (d *DiskCollector) usage(mountPoint string) (*disk.UsageStat, error) {
+	d.mu.Lock()
+	if d.pending == nil {
+		d.pending = make(map[string]bool)
+	}
+	if d.pending[mountPoint] {
+		d.mu.Unlock()
+		return nil, errMountStale
+	}
+	d.pending[mountPoint] = true
+	d.mu.Unlock()
+
+	done := make(chan diskStatResult, 1)
+	go func() {
+		usage, err := disk.Usage(mountPoint)
+		d.mu.Lock()
+		delete(d.pending, mountPoint)
+		d.mu.Unlock()
+		done <- diskStatResult{usage: usage, err: err}
+	}()
+
+	timer := time.NewTimer(diskStatTimeout)
+	defer timer.Stop()
+	select {
+	case result := <-done:
+		return result.usage, result.err
+	case <-timer.C:
+		return nil, errMountStale
+	}
+}
+
+// mountServer 网络文件系统的服务端地址
+// nfs: server:/export、[fe80::1]:/export；cifs: //server/share
+func mountServer(fsType, device string) string {
+	if !networkFSTypes[fsType] {
+		return ""
+	}
+	if strings.HasPrefix(device, "//") {
+		server, _, _ := strings.Cut(device[2:], "/")
+		return server
+	}
+	if strings.HasPrefix(device, "[") {
+		if end := strings.Index(device, "]"); end > 0 {
+			return device[1:end]
+		}
+	}
+	if server, _, ok := strings.Cut(device, ":"); ok {
+		return server
+	}
+	return ""
+}
+
 // shouldSkipPartition 判断是否跳过该分区
 func shouldSkipPartition(mountPoint string) bool {
 	// 跳过的挂载点列表
@@ -69,4 +206,4 @@ func shouldSkipPartition(mountPoint string) bool {
 	}
 
 	return false
-}
\ No newline at end of file
+}
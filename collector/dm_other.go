@@ -0,0 +1,18 @@
+//go:build !linux
+
+package collector
+
+import "errors"
+
+// dmTarget 设备映射表中一个目标的状态
+type dmTarget struct {
+	Start  uint64
+	Length uint64
+	Type   string
+	Status string
+}
+
+// dmStatus 非 Linux 平台没有 device-mapper
+func dmStatus(name string) ([]dmTarget, error) {
+	return nil, errors.New("device-mapper not supported on this platform")
+}
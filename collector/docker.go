@@ -0,0 +1,273 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"host-monitor-agent/models"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDockerSocket Docker Engine 默认 unix socket
+const DefaultDockerSocket = "/var/run/docker.sock"
+
+// dockerAPITimeout 单次 Docker API 请求超时
+const dockerAPITimeout = 5 * time.Second
+
+// dockerDiskUsageInterval /system/df 的刷新间隔
+// df 需要统计所有镜像层、容器可写层与卷的大小，开销远大于其他接口，两次刷新之间复用上次结果
+const dockerDiskUsageInterval = 5 * time.Minute
+
+// dockerInspectWorkers 并发 inspect 容器的请求数上限
+const dockerInspectWorkers = 4
+
+// dockerExitCodePattern 容器列表状态中的退出码，如 "Exited (137) 5 minutes ago"
+var dockerExitCodePattern = regexp.MustCompile(`^Exited \((-?\d+)\)`)
+
+// DockerCollector Docker Engine 采集器
+// /system/df 在后台刷新，不阻塞采集
+type DockerCollector struct {
+	SocketPath string // 为空时使用 DefaultDockerSocket
+
+	clientOnce sync.Once
+	client     *http.Client
+
+	mu            sync.Mutex
+	usage         *dockerDiskUsage
+	usageLastTime time.Time
+	usageRunning  bool
+}
+
+// dockerContainerSummary GET /containers/json 的返回项
+type dockerContainerSummary struct {
+	ID      string   `json:"Id"`
+	Names   []string `json:"Names"`
+	Image   string   `json:"Image"`
+	State   string   `json:"State"`
+	Status  string   `json:"Status"`
+	Created int64    `json:"Created"`
+}
+
+// dockerContainerInspect GET /containers/{id}/json 中用到的字段
+type dockerContainerInspect struct {
+	RestartCount int `json:"RestartCount"`
+	State        struct {
+		ExitCode int `json:"ExitCode"`
+		Health   *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+}
+
+// dockerInfo GET /info 中用到的字段
+type dockerInfo struct {
+	ServerVersion     string `json:"ServerVersion"`
+	Containers        int    `json:"Containers"`
+	ContainersRunning int    `json:"ContainersRunning"`
+	ContainersPaused  int    `json:"ContainersPaused"`
+	ContainersStopped int    `json:"ContainersStopped"`
+	Images            int    `json:"Images"`
+}
+
+// dockerDiskUsage GET /system/df 中用到的字段
+type dockerDiskUsage struct {
+	LayersSize int64 `json:"LayersSize"`
+	Containers []struct {
+		SizeRw int64 `json:"SizeRw"`
+	} `json:"Containers"`
+	Volumes []struct {
+		UsageData *struct {
+			Size int64 `json:"Size"`
+		} `json:"UsageData"`
+	} `json:"Volumes"`
+	BuildCache []struct {
+		Size int64 `json:"Size"`
+	} `json:"BuildCache"`
+}
+
+// Collect 通过 Docker Engine API 采集容器与守护进程状态
+// /info 成功后的后续请求失败时返回已获取的部分指标，错误写入 Error
+func (d *DockerCollector) Collect() (interface{}, error) {
+	metrics := models.DockerMetrics{}
+
+	var info dockerInfo
+	if err := d.get("/info", &info); err != nil {
+		return metrics, err
+	}
+	metrics.Available = true
+	metrics.ServerVersion = info.ServerVersion
+	metrics.ContainersTotal = info.Containers
+	metrics.ContainersRunning = info.ContainersRunning
+	metrics.ContainersPaused = info.ContainersPaused
+	metrics.ContainersStopped = info.ContainersStopped
+	metrics.Images = info.Images
+
+	d.refreshDiskUsage()
+	d.fillDiskUsage(&metrics)
+
+	var containers []dockerContainerSummary
+	if err := d.get("/containers/json?all=1", &containers); err != nil {
+		metrics.Error = err.Error()
+		return metrics, nil
+	}
+
+	metrics.Containers = make([]models.DockerContainer, len(containers))
+	for i, summary := range containers {
+		metrics.Containers[i] = newDockerContainer(summary)
+	}
+
+	// 重启次数只能从 inspect 获取，并发请求，单次采集耗时不超过 dockerAPITimeout 的若干倍
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, dockerInspectWorkers)
+	for i, summary := range containers {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(container *models.DockerContainer, id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			var inspect dockerContainerInspect
+			if err := d.get("/containers/"+id+"/json", &inspect); err != nil {
+				return
+			}
+			container.RestartCount = inspect.RestartCount
+			container.ExitCode = inspect.State.ExitCode
+			if inspect.State.Health != nil {
+				container.Health = inspect.State.Health.Status
+			}
+		}(&metrics.Containers[i], summary.ID)
+	}
+	wg.Wait()
+
+	for _, container := range metrics.Containers {
+		if container.Health == "unhealthy" {
+			metrics.ContainersUnhealthy++
+		}
+	}
+
+	return metrics, nil
+}
+
+// newDockerContainer 由容器列表项构造容器状态
+// 健康状态与退出码先从列表的状态文本解析，inspect 失败时仍然可用
+func newDockerContainer(summary dockerContainerSummary) models.DockerContainer {
+	container := models.DockerContainer{
+		ID:      shortContainerID(summary.ID),
+		Image:   summary.Image,
+		State:   summary.State,
+		Status:  summary.Status,
+		Created: time.Unix(summary.Created, 0).UTC().Format(time.RFC3339),
+	}
+	if len(summary.Names) > 0 {
+		container.Name = strings.TrimPrefix(summary.Names[0], "/")
+	}
+
+	// "Up 3 hours (unhealthy)"、"Up 5 seconds (health: starting)"
+	switch {
+	case strings.HasSuffix(summary.Status, "(unhealthy)"):
+		container.Health = "unhealthy"
+	case strings.HasSuffix(summary.Status, "(healthy)"):
+		container.Health = "healthy"
+	case strings.HasSuffix(summary.Status, "(health: starting)"):
+		container.Health = "starting"
+	}
+	if m := dockerExitCodePattern.FindStringSubmatch(summary.Status); m != nil {
+		container.ExitCode, _ = strconv.Atoi(m[1])
+	}
+
+	return container
+}
+
+// refreshDiskUsage 按 dockerDiskUsageInterval 在后台刷新磁盘占用，同一时间只有一个请求
+// 失败时同样等待下一个间隔，避免守护进程繁忙时每个周期重试
+func (d *DockerCollector) refreshDiskUsage() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.usageRunning || (d.usage != nil && time.Since(d.usageLastTime) < dockerDiskUsageInterval) {
+		return
+	}
+	d.usageRunning = true
+	d.usageLastTime = time.Now()
+
+	go func() {
+		var usage dockerDiskUsage
+		err := d.get("/system/df", &usage)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if err == nil {
+			d.usage = &usage
+		}
+		d.usageRunning = false
+	}()
+}
+
+// fillDiskUsage 填充最近一次的磁盘占用，首次刷新完成前容量字段为 0
+func (d *DockerCollector) fillDiskUsage(metrics *models.DockerMetrics) {
+	d.mu.Lock()
+	usage := d.usage
+	d.mu.Unlock()
+	if usage == nil {
+		return
+	}
+
+	metrics.ImagesSizeBytes = usage.LayersSize
+	for _, c := range usage.Containers {
+		metrics.ContainersSizeBytes += c.SizeRw
+	}
+	metrics.Volumes = len(usage.Volumes)
+	for _, v := range usage.Volumes {
+		// Size 为 -1 表示未统计
+		if v.UsageData != nil && v.UsageData.Size > 0 {
+			metrics.VolumesSizeBytes += v.UsageData.Size
+		}
+	}
+	for _, b := range usage.BuildCache {
+		metrics.BuildCacheSizeBytes += b.Size
+	}
+}
+
+// get 请求 Docker Engine API 并解析 JSON 响应
+func (d *DockerCollector) get(path string, v interface{}) error {
+	d.clientOnce.Do(func() {
+		socket := d.SocketPath
+		if socket == "" {
+			socket = DefaultDockerSocket
+		}
+		d.client = &http.Client{
+			Timeout: dockerAPITimeout,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var dialer net.Dialer
+					return dialer.DialContext(ctx, "unix", socket)
+				},
+			},
+		}
+	})
+
+	// 主机名仅用于构造 URL，实际连接走 unix socket
+	resp, err := d.client.Get("http://docker" + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("docker API %s: %s", path, resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// shortContainerID 截取容器ID前12位
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"host-monitor-agent/models"
+)
+
+// newFakeDocker 在临时 unix socket 上启动模拟的 Docker Engine API
+func newFakeDocker(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix socket not available: %v", err)
+	}
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return socket
+}
+
+func TestDockerCollector(t *testing.T) {
+	const (
+		webID = "3f4e8a1b2c9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091"
+		dbID  = "9a8b7c6d5e4f30211f2e3d4c5b6a79881726354453627180a9b8c7d6e5f40312"
+	)
+	var dfCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ServerVersion":"24.0.7","Containers":2,"ContainersRunning":1,"ContainersPaused":0,"ContainersStopped":1,"Images":5}`))
+	})
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("all") != "1" {
+			t.Errorf("containers/json without all=1: %s", r.URL)
+		}
+		w.Write([]byte(`[
+			{"Id":"` + webID + `","Names":["/web"],"Image":"nginx:1.25","State":"running","Status":"Up 3 hours (unhealthy)","Created":1700000000},
+			{"Id":"` + dbID + `","Names":["/db"],"Image":"postgres:16","State":"exited","Status":"Exited (137) 5 minutes ago","Created":1700000000}
+		]`))
+	})
+	mux.HandleFunc("/containers/"+webID+"/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"RestartCount":4,"State":{"ExitCode":0,"Health":{"Status":"unhealthy"}}}`))
+	})
+	mux.HandleFunc("/containers/"+dbID+"/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"RestartCount":0,"State":{"ExitCode":137}}`))
+	})
+	mux.HandleFunc("/system/df", func(w http.ResponseWriter, r *http.Request) {
+		dfCalls.Add(1)
+		w.Write([]byte(`{
+			"LayersSize":1000,
+			"Containers":[{"SizeRw":10},{"SizeRw":20}],
+			"Volumes":[{"UsageData":{"Size":300}},{"UsageData":{"Size":-1}}],
+			"BuildCache":[{"Size":7}]
+		}`))
+	})
+
+	collector := &DockerCollector{SocketPath: newFakeDocker(t, mux)}
+	result, err := collector.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := result.(models.DockerMetrics)
+
+	if !metrics.Available || metrics.ServerVersion != "24.0.7" || metrics.ContainersTotal != 2 ||
+		metrics.ContainersRunning != 1 || metrics.ContainersStopped != 1 || metrics.Images != 5 {
+		t.Errorf("daemon info = %+v", metrics)
+	}
+	if metrics.ContainersUnhealthy != 1 {
+		t.Errorf("unhealthy = %d, want 1", metrics.ContainersUnhealthy)
+	}
+	if len(metrics.Containers) != 2 {
+		t.Fatalf("got %d containers, want 2", len(metrics.Containers))
+	}
+	web, db := metrics.Containers[0], metrics.Containers[1]
+	if web.ID != webID[:12] || web.Name != "web" || web.Health != "unhealthy" || web.RestartCount != 4 {
+		t.Errorf("web = %+v", web)
+	}
+	if db.Name != "db" || db.State != "exited" || db.ExitCode != 137 || db.Health != "" {
+		t.Errorf("db = %+v", db)
+	}
+
+	// /system/df 在后台刷新，完成后的采集才有容量字段，间隔内不再重复请求
+	deadline := time.Now().Add(5 * time.Second)
+	for metrics.ImagesSizeBytes == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		result, err = collector.Collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		metrics = result.(models.DockerMetrics)
+	}
+	if metrics.ImagesSizeBytes != 1000 || metrics.ContainersSizeBytes != 30 || metrics.Volumes != 2 ||
+		metrics.VolumesSizeBytes != 300 || metrics.BuildCacheSizeBytes != 7 {
+		t.Errorf("disk usage = %+v", metrics)
+	}
+	if n := dfCalls.Load(); n != 1 {
+		t.Errorf("/system/df called %d times, want 1", n)
+	}
+}
+
+func TestDockerCollectorPartial(t *testing.T) {
+	const id = "3f4e8a1b2c9d0e1f2a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091"
+	var listFails atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/info", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ServerVersion":"24.0.7","Containers":2,"ContainersRunning":1,"ContainersStopped":1}`))
+	})
+	mux.HandleFunc("/containers/json", func(w http.ResponseWriter, r *http.Request) {
+		if listFails.Load() {
+			http.Error(w, "daemon busy", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`[
+			{"Id":"` + id + `","Names":["/web"],"Image":"nginx:1.25","State":"running","Status":"Up 3 hours (unhealthy)"},
+			{"Id":"missing","Names":["/db"],"Image":"postgres:16","State":"exited","Status":"Exited (137) 5 minutes ago"}
+		]`))
+	})
+	// inspect 全部失败
+	collector := &DockerCollector{SocketPath: newFakeDocker(t, mux)}
+
+	// inspect 失败时健康状态与退出码来自容器列表
+	result, err := collector.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	metrics := result.(models.DockerMetrics)
+	if len(metrics.Containers) != 2 || metrics.ContainersUnhealthy != 1 || metrics.Error != "" {
+		t.Fatalf("metrics = %+v", metrics)
+	}
+	if web, db := metrics.Containers[0], metrics.Containers[1]; web.Health != "unhealthy" || db.ExitCode != 137 {
+		t.Errorf("containers = %+v", metrics.Containers)
+	}
+
+	// 容器列表失败时保留守护进程信息
+	listFails.Store(true)
+	result, err = collector.Collect()
+	if err != nil {
+		t.Fatalf("Collect returned error %v, want partial metrics", err)
+	}
+	metrics = result.(models.DockerMetrics)
+	if !metrics.Available || metrics.ServerVersion != "24.0.7" || metrics.ContainersTotal != 2 || metrics.Error == "" {
+		t.Errorf("partial metrics = %+v", metrics)
+	}
+}
+
+func TestDockerCollectorUnavailable(t *testing.T) {
+	collector := &DockerCollector{SocketPath: filepath.Join(t.TempDir(), "missing.sock")}
+	result, err := collector.Collect()
+	if err == nil {
+		t.Fatal("expected error for missing socket")
+	}
+	if result.(models.DockerMetrics).Available {
+		t.Error("Available = true for missing socket")
+	}
+}
@@ -0,0 +1,35 @@
+package collector
+
+import (
+	"host-monitor-agent/models"
+	"time"
+)
+
+// EventSource 可产生变化事件的采集器
+// CollectAll 在每次 Collect 之后调用 DrainEvents 取走本轮事件
+type EventSource interface {
+	DrainEvents() []models.Event
+}
+
+// eventBuffer 采集器内部的事件暂存区，嵌入采集器即可实现 EventSource
+type eventBuffer struct {
+	pending []models.Event
+}
+
+// emit 记录一个事件
+func (b *eventBuffer) emit(source, eventType, message string, attributes map[string]string) {
+	b.pending = append(b.pending, models.Event{
+		Time:       time.Now().UTC().Format("2006-01-02 15:04:05 MST"),
+		Source:     source,
+		Type:       eventType,
+		Message:    message,
+		Attributes: attributes,
+	})
+}
+
+// DrainEvents 取走并清空暂存的事件
+func (b *eventBuffer) DrainEvents() []models.Event {
+	events := b.pending
+	b.pending = nil
+	return events
+}
@@ -0,0 +1,391 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"host-monitor-agent/models"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// integrityRecentChanges 指标中保留的最近变化数
+const integrityRecentChanges = 50
+
+// integrityMaxEvents 单次扫描最多产生的逐文件事件数，超出部分汇总为一条事件
+// 避免软件包升级时大量事件挤占事件缓存
+const integrityMaxEvents = 100
+
+// integrityEntry 基线中单个文件的状态
+type integrityEntry struct {
+	Type   string      `json:"type"`
+	SHA256 string      `json:"sha256,omitempty"`
+	Link   string      `json:"link,omitempty"`
+	Mode   fs.FileMode `json:"mode"`
+	UID    uint32      `json:"uid"`
+	GID    uint32      `json:"gid"`
+	Size   int64       `json:"size"`
+	MTime  int64       `json:"mtime"` // 纳秒
+	CTime  int64       `json:"ctime"` // 纳秒，内容或属性变化时更新，无法被 touch 伪造
+	Inode  uint64      `json:"inode"`
+}
+
+// integrityBaseline 持久化的基线
+type integrityBaseline struct {
+	Created time.Time                 `json:"created"`
+	Files   map[string]integrityEntry `json:"files"`
+}
+
+// integrityScanResult 后台扫描的结果
+type integrityScanResult struct {
+	start      time.Time
+	duration   time.Duration
+	current    map[string]integrityEntry
+	unreadable int
+	truncated  bool
+}
+
+// IntegrityCollector 关键文件完整性监控采集器
+// 扫描在独立的 goroutine 中执行，首次扫描或软件包升级后重新计算大量哈希时不阻塞其他采集器
+type IntegrityCollector struct {
+	Paths    []string      // 监控的文件与目录
+	Interval time.Duration // 扫描间隔
+	MaxFiles int           // 单次扫描的文件数上限
+	StateDir string        // 基线保存目录
+
+	eventBuffer
+	baseline *integrityBaseline
+	loaded   bool
+	lastScan time.Time
+	last     models.IntegrityMetrics  // 最近一次扫描结果，两次扫描之间复用
+	recent   []models.FileChange      // 新的在后
+	scanning chan integrityScanResult // 正在执行的扫描
+
+	added    uint64
+	removed  uint64
+	modified uint64
+}
+
+// Collect 按扫描间隔在后台扫描，扫描完成后对比基线，变化作为事件发出后基线随之更新
+// 扫描进行中返回上一次的结果
+func (c *IntegrityCollector) Collect() (interface{}, error) {
+	if len(c.Paths) == 0 {
+		return models.IntegrityMetrics{RecentChanges: []models.FileChange{}}, nil
+	}
+
+	if c.scanning != nil {
+		select {
+		case result := <-c.scanning:
+			c.scanning = nil
+			return c.apply(result), nil
+		default:
+			return c.last, nil
+		}
+	}
+
+	if !c.lastScan.IsZero() && time.Since(c.lastScan) < c.Interval {
+		return c.last, nil
+	}
+
+	if !c.loaded {
+		c.baseline = c.loadBaseline()
+		c.loaded = true
+		c.last = models.IntegrityMetrics{
+			Enabled:       true,
+			BaselineFile:  c.baselineFile(),
+			RecentChanges: []models.FileChange{},
+		}
+	}
+
+	// 扫描期间基线只读，结果在之后的 Collect 中应用
+	scanning := make(chan integrityScanResult, 1)
+	c.scanning = scanning
+	c.lastScan = time.Now()
+	go func(start time.Time, baseline *integrityBaseline) {
+		current, unreadable, truncated := c.scan(baseline)
+		scanning <- integrityScanResult{
+			start:      start,
+			duration:   time.Since(start),
+			current:    current,
+			unreadable: unreadable,
+			truncated:  truncated,
+		}
+	}(c.lastScan, c.baseline)
+
+	return c.last, nil
+}
+
+// apply 对比扫描结果与基线，更新基线与指标
+func (c *IntegrityCollector) apply(result integrityScanResult) models.IntegrityMetrics {
+	start, current, unreadable, truncated := result.start, result.current, result.unreadable, result.truncated
+
+	metrics := models.IntegrityMetrics{
+		Enabled:      true,
+		BaselineFile: c.baselineFile(),
+		Unreadable:   unreadable,
+		Truncated:    truncated,
+	}
+
+	if c.baseline == nil {
+		// 首次扫描只建立基线
+		c.baseline = &integrityBaseline{Created: start}
+	} else {
+		c.compare(c.baseline.Files, current, truncated, start)
+		if truncated {
+			// 扫描被截断时未遍历到的条目保留在基线中，否则之后遍历到时会被误报为新增
+			for path, entry := range c.baseline.Files {
+				if _, ok := current[path]; !ok && c.watched(path) {
+					current[path] = entry
+				}
+			}
+		}
+	}
+	c.baseline.Files = current
+	metrics.Files = len(current)
+
+	if err := c.saveBaseline(); err != nil {
+		metrics.Error = err.Error()
+	}
+
+	metrics.BaselineCreated = c.baseline.Created.UTC().Format("2006-01-02 15:04:05 MST")
+	metrics.LastScan = start.UTC().Format("2006-01-02 15:04:05 MST")
+	metrics.ScanSeconds = math.Round(result.duration.Seconds()*1000) / 1000
+	metrics.Added = c.added
+	metrics.Removed = c.removed
+	metrics.Modified = c.modified
+	metrics.RecentChanges = reversed(c.recent)
+
+	c.last = metrics
+	return metrics
+}
+
+// scan 遍历所有监控路径，返回当前状态
+// 元数据（inode、大小、mtime、ctime）与基线一致的文件复用基线中的哈希
+func (c *IntegrityCollector) scan(baseline *integrityBaseline) (map[string]integrityEntry, int, bool) {
+	current := make(map[string]integrityEntry)
+	unreadable := 0
+	truncated := false
+
+	for _, root := range c.Paths {
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if !os.IsNotExist(err) {
+					unreadable++
+				}
+				if d != nil && d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if c.MaxFiles > 0 && len(current) >= c.MaxFiles {
+				truncated = true
+				return filepath.SkipAll
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+
+			entry := integrityEntry{
+				Mode:  info.Mode(),
+				Size:  info.Size(),
+				MTime: info.ModTime().UnixNano(),
+			}
+			entry.UID, entry.GID, entry.CTime, entry.Inode = fileOwnership(info)
+
+			switch {
+			case info.Mode().IsRegular():
+				entry.Type = "file"
+				entry.SHA256 = cachedHash(baseline, path, entry)
+				if entry.SHA256 == "" {
+					unreadable++
+				}
+			case info.IsDir():
+				entry.Type = "dir"
+				entry.Size = 0
+			case info.Mode()&fs.ModeSymlink != 0:
+				entry.Type = "symlink"
+				entry.Link, _ = os.Readlink(path)
+			default:
+				// 设备、套接字、管道不监控
+				return nil
+			}
+
+			current[path] = entry
+			return nil
+		})
+	}
+
+	return current, unreadable, truncated
+}
+
+// cachedHash 元数据未变化时复用基线哈希，否则重新计算
+func cachedHash(baseline *integrityBaseline, path string, entry integrityEntry) string {
+	if baseline != nil {
+		if old, ok := baseline.Files[path]; ok && old.SHA256 != "" &&
+			old.Inode == entry.Inode && old.Size == entry.Size &&
+			old.MTime == entry.MTime && old.CTime == entry.CTime {
+			return old.SHA256
+		}
+	}
+	hash, err := hashFile(path)
+	if err != nil {
+		return ""
+	}
+	return hash
+}
+
+// compare 对比基线与当前状态，记录变化并发出事件
+// 文件数超过上限时未扫描到的文件不视为删除
+func (c *IntegrityCollector) compare(old, current map[string]integrityEntry, truncated bool, now time.Time) {
+	var changes []models.FileChange
+	at := now.UTC().Format("2006-01-02 15:04:05 MST")
+
+	for path, entry := range current {
+		before, ok := old[path]
+		switch {
+		case !ok:
+			c.added++
+			changes = append(changes, models.FileChange{Path: path, Change: "added", Time: at, New: entry.state()})
+		case integrityModified(before, entry):
+			c.modified++
+			changes = append(changes, models.FileChange{Path: path, Change: "modified", Time: at, Old: before.state(), New: entry.state()})
+		}
+	}
+	if !truncated {
+		for path, entry := range old {
+			// 配置中移除的路径不视为文件被删除
+			if _, ok := current[path]; ok || !c.watched(path) {
+				continue
+			}
+			c.removed++
+			changes = append(changes, models.FileChange{Path: path, Change: "removed", Time: at, Old: entry.state()})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	for i, change := range changes {
+		c.recent = appendBounded(c.recent, change, integrityRecentChanges)
+		if i < integrityMaxEvents {
+			c.emit("integrity", "file_"+change.Change,
+				fmt.Sprintf("%s %s", change.Path, change.Change), changeAttributes(change))
+		}
+	}
+	if len(changes) > integrityMaxEvents {
+		c.emit("integrity", "file_changes_truncated",
+			fmt.Sprintf("%d more file changes not reported individually", len(changes)-integrityMaxEvents),
+			map[string]string{"total": fmt.Sprint(len(changes))})
+	}
+}
+
+// watched 检查路径是否位于当前配置的监控路径下
+func (c *IntegrityCollector) watched(path string) bool {
+	for _, root := range c.Paths {
+		root = strings.TrimRight(root, "/")
+		if path == root || strings.HasPrefix(path, root+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// integrityModified 内容、类型、权限、属主或链接目标变化视为修改
+// 无权限读取时哈希为空，不参与比较；单纯的 mtime 变化（touch）不视为修改
+func integrityModified(before, after integrityEntry) bool {
+	if before.SHA256 != "" && after.SHA256 != "" && before.SHA256 != after.SHA256 {
+		return true
+	}
+	return before.Type != after.Type || before.Mode != after.Mode ||
+		before.UID != after.UID || before.GID != after.GID || before.Link != after.Link
+}
+
+// state 转换为指标中的文件状态
+func (e integrityEntry) state() *models.FileState {
+	return &models.FileState{
+		Type:   e.Type,
+		SHA256: e.SHA256,
+		Link:   e.Link,
+		Mode:   e.Mode.String(),
+		UID:    e.UID,
+		GID:    e.GID,
+		Size:   e.Size,
+		MTime:  time.Unix(0, e.MTime).UTC().Format("2006-01-02 15:04:05 MST"),
+	}
+}
+
+// changeAttributes 事件属性：变化前后的哈希、权限、属主与修改时间
+func changeAttributes(change models.FileChange) map[string]string {
+	attrs := map[string]string{"path": change.Path}
+	for prefix, state := range map[string]*models.FileState{"old_": change.Old, "new_": change.New} {
+		if state == nil {
+			continue
+		}
+		attrs[prefix+"sha256"] = state.SHA256
+		attrs[prefix+"mode"] = state.Mode
+		attrs[prefix+"owner"] = fmt.Sprintf("%d:%d", state.UID, state.GID)
+		attrs[prefix+"mtime"] = state.MTime
+		if state.Link != "" {
+			attrs[prefix+"link"] = state.Link
+		}
+	}
+	return attrs
+}
+
+// hashFile 计算文件内容的 SHA-256
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// baselineFile 基线文件路径
+func (c *IntegrityCollector) baselineFile() string {
+	return filepath.Join(c.StateDir, "integrity-baseline.json")
+}
+
+// loadBaseline 读取磁盘上的基线，不存在或损坏时返回 nil
+func (c *IntegrityCollector) loadBaseline() *integrityBaseline {
+	data, err := os.ReadFile(c.baselineFile())
+	if err != nil {
+		return nil
+	}
+	var baseline integrityBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil || baseline.Files == nil {
+		return nil
+	}
+	return &baseline
+}
+
+// saveBaseline 将基线写入磁盘（先写临时文件再改名）
+func (c *IntegrityCollector) saveBaseline() error {
+	data, err := json.Marshal(c.baseline)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.StateDir, 0755); err != nil {
+		return err
+	}
+	tmp := c.baselineFile() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.baselineFile())
+}
@@ -0,0 +1,17 @@
+//go:build linux
+
+package collector
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// fileOwnership 返回文件的属主、属组、ctime（纳秒）与 inode
+func fileOwnership(info fs.FileInfo) (uid, gid uint32, ctime int64, inode uint64) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, 0, 0
+	}
+	return stat.Uid, stat.Gid, int64(stat.Ctim.Sec)*1e9 + int64(stat.Ctim.Nsec), uint64(stat.Ino)
+}
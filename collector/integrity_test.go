@@ -0,0 +1,139 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"host-monitor-agent/models"
+)
+
+// integrityScan 启动一次后台扫描，等待完成后返回指标与本次产生的事件
+func integrityScan(t *testing.T, c *IntegrityCollector) (models.IntegrityMetrics, []models.Event) {
+	t.Helper()
+	if _, err := c.Collect(); err != nil {
+		t.Fatal(err)
+	}
+	if c.scanning == nil {
+		t.Fatal("scan not started")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.scanning != nil && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		result, err := c.Collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.scanning == nil {
+			return result.(models.IntegrityMetrics), c.DrainEvents()
+		}
+	}
+	t.Fatal("scan did not finish")
+	return models.IntegrityMetrics{}, nil
+}
+
+// writeFiles 在目录中写入文件
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestIntegrityDetectsChanges(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"a": "1", "b": "2"})
+	c := &IntegrityCollector{Paths: []string{root}, StateDir: t.TempDir()}
+
+	// 首次扫描只建立基线
+	metrics, events := integrityScan(t, c)
+	if metrics.Files != 3 || len(events) != 0 {
+		t.Fatalf("baseline scan: files = %d, events = %+v", metrics.Files, events)
+	}
+
+	writeFiles(t, root, map[string]string{"a": "changed", "c": "3"})
+	os.Remove(filepath.Join(root, "b"))
+	metrics, events = integrityScan(t, c)
+	got := map[string]string{}
+	for _, event := range events {
+		got[event.Attributes["path"]] = event.Type
+	}
+	want := map[string]string{
+		filepath.Join(root, "a"): "file_modified",
+		filepath.Join(root, "b"): "file_removed",
+		filepath.Join(root, "c"): "file_added",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for path, eventType := range want {
+		if got[path] != eventType {
+			t.Errorf("%s: event %q, want %q", path, got[path], eventType)
+		}
+	}
+	if metrics.Added != 1 || metrics.Removed != 1 || metrics.Modified != 1 {
+		t.Errorf("counters = %d/%d/%d", metrics.Added, metrics.Removed, metrics.Modified)
+	}
+}
+
+func TestIntegrityTruncatedScanKeepsBaseline(t *testing.T) {
+	root := t.TempDir()
+	stateDir := t.TempDir()
+	writeFiles(t, root, map[string]string{"a": "1", "b": "2", "c": "3"})
+
+	// 不限文件数时建立完整基线
+	integrityScan(t, &IntegrityCollector{Paths: []string{root}, StateDir: stateDir})
+
+	// 上限为 3（目录本身、a、b），c 未被遍历到但仍保留在基线中
+	c := &IntegrityCollector{Paths: []string{root}, StateDir: stateDir, MaxFiles: 3}
+	metrics, events := integrityScan(t, c)
+	if !metrics.Truncated || metrics.Files != 4 || len(events) != 0 {
+		t.Fatalf("truncated scan: %+v, events = %+v", metrics, events)
+	}
+
+	// 删除 a 后遍历到 c，不应误报为新增
+	os.Remove(filepath.Join(root, "a"))
+	metrics, events = integrityScan(t, c)
+	for _, event := range events {
+		if event.Type == "file_added" {
+			t.Errorf("false added event: %+v", event)
+		}
+	}
+	if metrics.Added != 0 {
+		t.Errorf("added = %d, want 0", metrics.Added)
+	}
+}
+
+func TestIntegrityScanDoesNotBlockCollect(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"a": "1"})
+	c := &IntegrityCollector{Paths: []string{root}, StateDir: t.TempDir(), Interval: time.Hour}
+
+	// 扫描在后台进行，首次 Collect 立即返回空结果
+	result, err := c.Collect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metrics := result.(models.IntegrityMetrics); !metrics.Enabled || metrics.LastScan != "" {
+		t.Errorf("first collect = %+v", metrics)
+	}
+
+	// 扫描完成后应用结果，间隔内不再扫描
+	deadline := time.Now().Add(5 * time.Second)
+	var metrics models.IntegrityMetrics
+	for metrics.LastScan == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+		result, _ := c.Collect()
+		metrics = result.(models.IntegrityMetrics)
+	}
+	if metrics.Files != 2 || c.scanning != nil {
+		t.Errorf("after scan = %+v", metrics)
+	}
+	c.Collect()
+	if c.scanning != nil {
+		t.Error("new scan started within the interval")
+	}
+}
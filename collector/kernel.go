@@ -0,0 +1,230 @@
+package collector
+
+import (
+	"bufio"
+	"host-monitor-agent/models"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KernelCollector 内核活动计数器采集器
+type KernelCollector struct {
+	// 上一次采集的值，用于计算速率
+	last     *models.KernelMetrics
+	lastIRQs map[string][]uint64
+	lastSoft map[string][]uint64
+	lastTime time.Time
+}
+
+// Collect 采集上下文切换、中断、fork、缺页与内存回收等计数器
+func (k *KernelCollector) Collect() (interface{}, error) {
+	stat, err := parseProcStat("/proc/stat")
+	if err != nil {
+		return models.KernelMetrics{}, err
+	}
+
+	metrics := models.KernelMetrics{
+		ContextSwitches: stat["ctxt"],
+		Interrupts:      stat["intr"],
+		SoftIRQs:        stat["softirq"],
+		Forks:           stat["processes"],
+		ProcsRunning:    stat["procs_running"],
+		ProcsBlocked:    stat["procs_blocked"],
+	}
+
+	if vmstat, err := readKeyValueFile("/proc/vmstat"); err == nil {
+		metrics.PageFaults = vmstat["pgfault"]
+		metrics.MajorFaults = vmstat["pgmajfault"]
+		if metrics.PageFaults >= metrics.MajorFaults {
+			metrics.MinorFaults = metrics.PageFaults - metrics.MajorFaults
+		}
+		// pgscan_anon/pgscan_file 与按回收者划分的计数重复，不计入
+		for _, source := range []string{"kswapd", "direct", "khugepaged", "proactive"} {
+			metrics.PgScan += vmstat["pgscan_"+source]
+			metrics.PgSteal += vmstat["pgsteal_"+source]
+		}
+		metrics.OOMKills = vmstat["oom_kill"]
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(k.lastTime).Seconds()
+
+	if k.last != nil {
+		prev := k.last
+		metrics.Rates = models.KernelRates{
+			ContextSwitchesPerSec: roundRate(counterRate(metrics.ContextSwitches, prev.ContextSwitches, elapsed)),
+			InterruptsPerSec:      roundRate(counterRate(metrics.Interrupts, prev.Interrupts, elapsed)),
+			SoftIRQsPerSec:        roundRate(counterRate(metrics.SoftIRQs, prev.SoftIRQs, elapsed)),
+			ForksPerSec:           roundRate(counterRate(metrics.Forks, prev.Forks, elapsed)),
+			MajorFaultsPerSec:     roundRate(counterRate(metrics.MajorFaults, prev.MajorFaults, elapsed)),
+			MinorFaultsPerSec:     roundRate(counterRate(metrics.MinorFaults, prev.MinorFaults, elapsed)),
+			PgScanPerSec:          roundRate(counterRate(metrics.PgScan, prev.PgScan, elapsed)),
+			PgStealPerSec:         roundRate(counterRate(metrics.PgSteal, prev.PgSteal, elapsed)),
+			OOMKillsPerSec:        roundRate(counterRate(metrics.OOMKills, prev.OOMKills, elapsed)),
+		}
+	}
+
+	// 每CPU中断分布
+	irqs, irqDevices, _ := parsePerCPUTable("/proc/interrupts")
+	soft, softOrder, _ := parsePerCPUTable("/proc/softirqs")
+
+	if k.lastIRQs != nil {
+		var perCPU []float64
+		for _, irq := range irqDevices.order {
+			rates, total := perCPURates(irqs[irq], k.lastIRQs[irq], elapsed)
+			if rates == nil {
+				continue
+			}
+			if len(perCPU) < len(rates) {
+				perCPU = append(perCPU, make([]float64, len(rates)-len(perCPU))...)
+			}
+			for i, r := range rates {
+				perCPU[i] += r
+			}
+			if total > 0 {
+				metrics.IRQs = append(metrics.IRQs, models.IRQStat{
+					IRQ:          irq,
+					Device:       irqDevices.desc[irq],
+					PerSec:       roundRate(total),
+					PerCPUPerSec: roundRates(rates),
+				})
+			}
+		}
+		metrics.IRQsPerCPU = roundRates(perCPU)
+	}
+
+	if k.lastSoft != nil {
+		for _, name := range softOrder.order {
+			rates, total := perCPURates(soft[name], k.lastSoft[name], elapsed)
+			if rates == nil {
+				continue
+			}
+			metrics.SoftIRQsPerCPU = append(metrics.SoftIRQsPerCPU, models.SoftIRQStat{
+				Name:         name,
+				PerSec:       roundRate(total),
+				PerCPUPerSec: roundRates(rates),
+			})
+		}
+	}
+
+	last := metrics
+	k.last = &last
+	k.lastIRQs = irqs
+	k.lastSoft = soft
+	k.lastTime = now
+
+	return metrics, nil
+}
+
+// parseProcStat 解析 /proc/stat 中的单值计数行
+// intr 与 softirq 行只取第一个字段（总数）
+func parseProcStat(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	// intr 行在中断较多的机器上很长
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+
+	return values, scanner.Err()
+}
+
+// perCPUTableIndex 每CPU表的行顺序与描述
+type perCPUTableIndex struct {
+	order []string
+	desc  map[string]string
+}
+
+// parsePerCPUTable 解析 /proc/interrupts 与 /proc/softirqs
+// 首行为 CPU 列头，之后每行为 "名称: 每CPU计数... [描述]"
+func parsePerCPUTable(path string) (map[string][]uint64, perCPUTableIndex, error) {
+	index := perCPUTableIndex{desc: map[string]string{}}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, index, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	if !scanner.Scan() {
+		return nil, index, scanner.Err()
+	}
+	cpuCount := len(strings.Fields(scanner.Text()))
+
+	table := make(map[string][]uint64)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		name := strings.TrimSuffix(fields[0], ":")
+		var counts []uint64
+		i := 1
+		for ; i < len(fields) && len(counts) < cpuCount; i++ {
+			v, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				break
+			}
+			counts = append(counts, v)
+		}
+		// ERR/MIS 等行只有一个全局计数，不参与每CPU分布
+		if len(counts) != cpuCount {
+			continue
+		}
+
+		table[name] = counts
+		index.order = append(index.order, name)
+		// 编号中断的最后一列为设备名，LOC/RES 等架构中断为整段描述
+		if i < len(fields) {
+			if _, err := strconv.Atoi(name); err == nil {
+				index.desc[name] = fields[len(fields)-1]
+			} else {
+				index.desc[name] = strings.Join(fields[i:], " ")
+			}
+		}
+	}
+
+	return table, index, scanner.Err()
+}
+
+// perCPURates 计算每CPU速率与总速率，CPU数变化（热插拔）时返回 nil
+func perCPURates(current, previous []uint64, seconds float64) ([]float64, float64) {
+	if len(current) == 0 || len(current) != len(previous) {
+		return nil, 0
+	}
+
+	rates := make([]float64, len(current))
+	var total float64
+	for i := range current {
+		rates[i] = counterRate(current[i], previous[i], seconds)
+		total += rates[i]
+	}
+
+	return rates, total
+}
+
+// roundRates 逐项保留1位小数
+func roundRates(rates []float64) []float64 {
+	for i := range rates {
+		rates[i] = roundRate(rates[i])
+	}
+	return rates
+}
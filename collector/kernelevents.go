@@ -0,0 +1,256 @@
+package collector
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// 内核日志事件类型
+const (
+	kernelEventOOMKill       = "oom_kill"
+	kernelEventHungTask      = "hung_task"
+	kernelEventSoftLockup    = "soft_lockup"
+	kernelEventHardLockup    = "hard_lockup"
+	kernelEventHardwareError = "hardware_error"
+	kernelEventFSError       = "fs_error"
+	kernelEventLinkDown      = "link_down"
+	kernelEventSegfault      = "segfault"
+	kernelEventOops          = "kernel_oops"
+	kernelEventPanic         = "kernel_panic"
+)
+
+// kernelEventTypes 全部事件类型，计数中始终包含每一项
+var kernelEventTypes = []string{
+	kernelEventOOMKill,
+	kernelEventHungTask,
+	kernelEventSoftLockup,
+	kernelEventHardLockup,
+	kernelEventHardwareError,
+	kernelEventFSError,
+	kernelEventLinkDown,
+	kernelEventSegfault,
+	kernelEventOops,
+	kernelEventPanic,
+}
+
+// kernelEventRule 单条分类规则，attrs 从子匹配中提取事件属性
+type kernelEventRule struct {
+	eventType string
+	pattern   *regexp.Regexp
+	attrs     func(m []string) map[string]string
+}
+
+// kernelEventRules 按顺序匹配，第一条命中的规则决定类型
+// 用户态的 segfault/traps 须在内核 general protection fault 之前匹配
+var kernelEventRules = []kernelEventRule{
+	// Out of memory: Killed process 1234 (java) total-vm:..., anon-rss:...kB, file-rss:...kB, shmem-rss:...kB
+	// Memory cgroup out of memory: Killed process ...
+	// 旧内核先输出 "Kill process ... or sacrifice child"，随后单独一行 "Killed process ..."
+	{kernelEventOOMKill,
+		regexp.MustCompile(`^(?:(Memory cgroup out of memory|Out of memory)[^:]*: )?Killed process (\d+) \((.*?)\)(.*)`),
+		func(m []string) map[string]string {
+			constraint := "global"
+			if m[1] == "Memory cgroup out of memory" {
+				constraint = "memcg"
+			}
+			attrs := map[string]string{"pid": m[2], "process": m[3], "constraint": constraint}
+			if rss, ok := oomRSSBytes(m[4]); ok {
+				attrs["rss_bytes"] = strconv.FormatUint(rss, 10)
+			}
+			return attrs
+		}},
+
+	// INFO: task kworker/0:1:123 blocked for more than 120 seconds.
+	{kernelEventHungTask,
+		regexp.MustCompile(`^INFO: task (.+):(\d+) blocked for more than (\d+) seconds`),
+		func(m []string) map[string]string {
+			return map[string]string{"process": m[1], "pid": m[2], "seconds": m[3]}
+		}},
+
+	// watchdog: BUG: soft lockup - CPU#3 stuck for 22s! [java:1234]
+	{kernelEventSoftLockup,
+		regexp.MustCompile(`BUG: soft lockup - CPU#(\d+) stuck for (\d+)s! \[(.+):(\d+)\]`),
+		func(m []string) map[string]string {
+			return map[string]string{"cpu": m[1], "seconds": m[2], "process": m[3], "pid": m[4]}
+		}},
+
+	// NMI watchdog: Watchdog detected hard LOCKUP on cpu 5
+	{kernelEventHardLockup,
+		regexp.MustCompile(`Watchdog detected hard LOCKUP on cpu (\d+)`),
+		func(m []string) map[string]string {
+			return map[string]string{"cpu": m[1]}
+		}},
+
+	// mce: [Hardware Error]: Machine check events logged
+	// mce: [Hardware Error]: CPU 0: Machine Check: 0 Bank 8: ...
+	// 启动时的 "mce: CPU0: Thermal monitoring enabled" 等信息不计入
+	{kernelEventHardwareError,
+		regexp.MustCompile(`^mce: \[Hardware Error\]: (.*)`),
+		func(m []string) map[string]string {
+			return map[string]string{"kind": "mce", "detail": m[1]}
+		}},
+
+	// EDAC MC0: 1 CE memory read error on CPU_SrcID#0_Ha#0_Chan#1_DIMM#0 ...
+	{kernelEventHardwareError,
+		regexp.MustCompile(`^EDAC (\S+?): (\d+) (CE|UE) (.*)`),
+		func(m []string) map[string]string {
+			severity := "corrected"
+			if m[3] == "UE" {
+				severity = "uncorrected"
+			}
+			return map[string]string{"kind": "edac", "controller": m[1], "count": m[2], "severity": severity, "detail": m[4]}
+		}},
+
+	// {1}[Hardware Error]: Hardware error from APEI Generic Hardware Error Source: 0
+	{kernelEventHardwareError,
+		regexp.MustCompile(`\[Hardware Error\]: (.*)`),
+		func(m []string) map[string]string {
+			return map[string]string{"kind": "ghes", "detail": m[1]}
+		}},
+
+	// Memory failure: 0x12345: recovery action for dirty LRU page: Recovered
+	{kernelEventHardwareError,
+		regexp.MustCompile(`^Memory failure: (\S+?): (.*)`),
+		func(m []string) map[string]string {
+			return map[string]string{"kind": "memory_failure", "pfn": m[1], "detail": m[2]}
+		}},
+
+	// EXT4-fs error (device sda1): ext4_lookup:1855: inode #2: comm ls: deleted inode referenced: 12
+	// BTRFS error (device sdb): bdev /dev/sdb errs: wr 0, rd 1, flush 0, corrupt 0, gen 0
+	{kernelEventFSError,
+		regexp.MustCompile(`^(EXT[234]-fs|BTRFS|F2FS-fs) (?:error|critical) \(device ([^)\s]+)\)`),
+		fsErrorAttrs},
+
+	// EXT4-fs (sda1): Remounting filesystem read-only
+	// XFS (dm-0): Corruption detected. Unmount and run xfs_repair
+	// XFS (dm-0): log I/O error -5
+	// BTRFS info (device sdb): forced readonly
+	// 挂载参数中的 errors=remount-ro 不应命中，因此只匹配明确的错误描述
+	{kernelEventFSError,
+		regexp.MustCompile(`^(EXT[234]-fs|XFS|BTRFS|F2FS-fs)[^(:]*\((?:device )?([^)\s]+)\):? .*(?:Remounting filesystem read-only|forced readonly|[Ss]hutting down filesystem|[Ff]ilesystem has been shut down|Corruption|[Aa]borting journal|I/O error)`),
+		fsErrorAttrs},
+
+	// bond0: (slave eth1): link status definitely down, disabling slave
+	{kernelEventLinkDown,
+		regexp.MustCompile(`^(\S+?): \(slave ([^)]+)\): link status definitely down`),
+		func(m []string) map[string]string {
+			return map[string]string{"interface": m[2], "bond": m[1]}
+		}},
+
+	// bonding: bond0: link status definitely down for interface eth1, disabling it
+	{kernelEventLinkDown,
+		regexp.MustCompile(`(\S+?): link status definitely down for interface ([^,\s]+)`),
+		func(m []string) map[string]string {
+			return map[string]string{"interface": m[2], "bond": m[1]}
+		}},
+
+	// e1000e 0000:00:19.0 eth0: NIC Link is Down
+	// igb 0000:01:00.0 eno1: igb: eno1 NIC Link is Down
+	// ixgbe 0000:03:00.0 enp3s0: NIC Link is Down
+	// r8169 0000:02:00.0 enp2s0: Link is Down
+	// mlx5_core 0000:3b:00.0 ens1f0np0: Link down
+	{kernelEventLinkDown,
+		regexp.MustCompile(`(\S+?):? (?:NIC )?Link (?:is )?[Dd]own`),
+		func(m []string) map[string]string {
+			return map[string]string{"interface": m[1]}
+		}},
+
+	// python3[1234]: segfault at 0 ip 00007f...  sp 00007ff... error 4 in libc.so.6[7f...+1b2000]
+	{kernelEventSegfault,
+		regexp.MustCompile(`^(.+?)\[(\d+)\]: segfault at (\S+) ip (\S+)(?:.*? error (\d+))?(?:.*? in ([^\[\s]+)\[)?`),
+		func(m []string) map[string]string {
+			attrs := map[string]string{"process": m[1], "pid": m[2], "kind": "segfault", "address": m[3], "ip": m[4]}
+			if m[6] != "" {
+				attrs["module"] = m[6]
+			}
+			return attrs
+		}},
+
+	// traps: app[1234] general protection fault ip:4005d4 sp:7ffd... error:0 in app[400000+1000]
+	// traps: app[1234] trap divide error ip:4004f6 sp:7ffd... error:0 in app[400000+1000]
+	{kernelEventSegfault,
+		regexp.MustCompile(`^traps: (.+?)\[(\d+)\] (.+?) ip:(\S+)(?:.*? in ([^\[\s]+)\[)?`),
+		func(m []string) map[string]string {
+			attrs := map[string]string{"process": m[1], "pid": m[2], "kind": m[3], "ip": m[4]}
+			if m[5] != "" {
+				attrs["module"] = m[5]
+			}
+			return attrs
+		}},
+
+	// Kernel panic - not syncing: Fatal exception
+	{kernelEventPanic,
+		regexp.MustCompile(`^Kernel panic - not syncing: (.*)`),
+		func(m []string) map[string]string {
+			return map[string]string{"reason": m[1]}
+		}},
+
+	// BUG: kernel NULL pointer dereference, address: 0000000000000000
+	// BUG: unable to handle page fault for address: ffff...
+	// kernel BUG at mm/slub.c:123!
+	// general protection fault, probably for non-canonical address 0x...: 0000 [#1] SMP PTI
+	// Oops: 0000 [#1] SMP
+	{kernelEventOops,
+		regexp.MustCompile(`^(BUG: (?:kernel NULL pointer|unable to handle|Bad page|scheduling while atomic|KASAN|spinlock)|kernel BUG at |general protection fault|Oops: )(.*)`),
+		func(m []string) map[string]string {
+			return map[string]string{"detail": m[1] + m[2]}
+		}},
+}
+
+// classifyKernelMessage 对内核消息分类，不属于任何类型时返回 false
+func classifyKernelMessage(message string) (string, map[string]string, bool) {
+	for _, rule := range kernelEventRules {
+		if m := rule.pattern.FindStringSubmatch(message); m != nil {
+			return rule.eventType, rule.attrs(m), true
+		}
+	}
+	return "", nil, false
+}
+
+// oomRSSPattern OOM 消息中被杀进程的内存占用
+var oomRSSPattern = regexp.MustCompile(`(anon|file|shmem)-rss:(\d+)kB`)
+
+// oomRSSBytes 计算 anon-rss、file-rss 与 shmem-rss 之和（字节）
+func oomRSSBytes(detail string) (uint64, bool) {
+	matches := oomRSSPattern.FindAllStringSubmatch(detail, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	var total uint64
+	for _, m := range matches {
+		kb, _ := strconv.ParseUint(m[2], 10, 64)
+		total += kb * 1024
+	}
+	return total, true
+}
+
+// fsReadOnlyPattern 文件系统被切换为只读或关闭
+var fsReadOnlyPattern = regexp.MustCompile(`read-only|readonly|[Ss]hut(?:ting)? ?down`)
+
+// fsErrorAttrs 文件系统错误的属性，m[0] 为从行首开始的匹配内容
+func fsErrorAttrs(m []string) map[string]string {
+	attrs := map[string]string{"filesystem": fsName(m[1]), "device": m[2]}
+	if fsReadOnlyPattern.MatchString(m[0]) {
+		attrs["read_only"] = "true"
+	}
+	return attrs
+}
+
+// fsName 日志前缀对应的文件系统名
+func fsName(prefix string) string {
+	switch prefix {
+	case "EXT2-fs":
+		return "ext2"
+	case "EXT3-fs":
+		return "ext3"
+	case "EXT4-fs":
+		return "ext4"
+	case "XFS":
+		return "xfs"
+	case "BTRFS":
+		return "btrfs"
+	case "F2FS-fs":
+		return "f2fs"
+	}
+	return prefix
+}
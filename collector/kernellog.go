@@ -0,0 +1,285 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"host-monitor-agent/models"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// bootIDFile 每次开机随机生成的标识，用于判断持久化的读取位置是否属于本次开机
+const bootIDFile = "/proc/sys/kernel/random/boot_id"
+
+// kernelLogRecentEvents 指标中保留的最近事件数
+const kernelLogRecentEvents = 50
+
+// kernelLogMaxEvents 单次采集最多产生的事件数，超出部分汇总为一条事件
+// 避免段错误循环或首次启动时积压的消息挤占事件缓存
+const kernelLogMaxEvents = 100
+
+// kernelLogMaxMessage 事件中保留的消息长度上限
+const kernelLogMaxMessage = 512
+
+// kernelLogSaveInterval 只读到未分类记录时保存读取位置的最短间隔
+// 位置落后会使重启后把已处理、已被覆盖的记录计入丢失数
+const kernelLogSaveInterval = time.Minute
+
+// dmesgTimeout dmesg 的超时时间
+const dmesgTimeout = 10 * time.Second
+
+// dmesgLinePattern dmesg -r 输出：<优先级>[秒.微秒] 消息
+var dmesgLinePattern = regexp.MustCompile(`^<(\d+)>\[\s*(\d+)\.(\d+)\] ?(.*)`)
+
+// kernelRecord 一条内核日志记录
+type kernelRecord struct {
+	Seq      uint64 // 仅 /dev/kmsg 提供
+	Usec     int64  // 开机以来的微秒数
+	Priority int
+	Message  string
+}
+
+// kernelLogState 持久化的读取位置与计数，重启后不重复上报本次开机已处理的消息
+type kernelLogState struct {
+	BootID string            `json:"boot_id"`
+	Seq    uint64            `json:"seq"`  // 下一条待读取的 /dev/kmsg 序号
+	Usec   int64             `json:"usec"` // 已处理的最后一条记录的时间戳，dmesg 以此去重
+	Counts map[string]uint64 `json:"counts"`
+}
+
+// KernelLogCollector 内核日志异常事件采集器
+// 优先读取 /dev/kmsg，无法打开时（如容器内或权限不足）退回到 dmesg -r
+type KernelLogCollector struct {
+	StateDir string // 读取位置保存目录
+
+	eventBuffer
+	reader   *kmsgReader
+	state    *kernelLogState
+	bootTime time.Time
+	recent   []models.KernelLogEvent // 新的在后
+	lost     uint64
+	emitted  int // 本次采集已产生的事件数
+
+	savedSeq  uint64 // 已写入磁盘的读取位置
+	savedUsec int64
+	lastSave  time.Time
+}
+
+// Collect 读取新的内核日志记录并分类
+func (k *KernelLogCollector) Collect() (interface{}, error) {
+	metrics := models.KernelLogMetrics{
+		Counts:       make(map[string]uint64, len(kernelEventTypes)),
+		RecentEvents: []models.KernelLogEvent{},
+	}
+
+	if k.state == nil {
+		k.state = k.loadState()
+		k.savedSeq, k.savedUsec = k.state.Seq, k.state.Usec
+		if stat, err := parseProcStat("/proc/stat"); err == nil {
+			k.bootTime = time.Unix(int64(stat["btime"]), 0)
+		}
+	}
+
+	k.emitted = 0
+	processed := 0
+	handle := func(record kernelRecord) {
+		if k.process(record) {
+			processed++
+		}
+	}
+
+	var err error
+	if k.reader == nil {
+		k.reader, err = openKmsg()
+	}
+	kmsgErr := err
+	if k.reader != nil {
+		metrics.Source = "kmsg"
+		err = k.reader.read(func(raw string) {
+			if record, ok := parseKmsgRecord(raw); ok {
+				handle(record)
+			}
+		})
+		if err != nil {
+			k.reader.close()
+			k.reader = nil
+		}
+	} else {
+		metrics.Source = "dmesg"
+		if err = readDmesg(handle); err != nil {
+			err = fmt.Errorf("%s: %v; %v", kmsgFile, kmsgErr, err)
+		}
+	}
+
+	if processed > kernelLogMaxEvents {
+		k.emit("kernel", "kernel_events_truncated",
+			fmt.Sprintf("%d more kernel events not reported individually", processed-kernelLogMaxEvents),
+			map[string]string{"total": strconv.Itoa(processed)})
+	}
+	// 有新事件时立即保存；只有未分类记录推进了读取位置时按间隔保存
+	// 读取位置写入失败只会导致重启后重复上报
+	advanced := k.state.Seq != k.savedSeq || k.state.Usec != k.savedUsec
+	if processed > 0 || (advanced && time.Since(k.lastSave) >= kernelLogSaveInterval) {
+		if k.saveState() == nil {
+			k.savedSeq, k.savedUsec, k.lastSave = k.state.Seq, k.state.Usec, time.Now()
+		}
+	}
+
+	if err != nil {
+		metrics.Error = err.Error()
+	} else {
+		metrics.Available = true
+	}
+	for _, eventType := range kernelEventTypes {
+		metrics.Counts[eventType] = k.state.Counts[eventType]
+	}
+	metrics.Lost = k.lost
+	metrics.RecentEvents = reversed(k.recent)
+
+	return metrics, nil
+}
+
+// process 跳过已处理的记录并对新记录分类，返回是否识别为事件
+func (k *KernelLogCollector) process(record kernelRecord) bool {
+	if k.reader != nil {
+		// /dev/kmsg：序号间隔即被覆盖的记录数
+		if record.Seq < k.state.Seq {
+			return false
+		}
+		if k.state.Seq > 0 && record.Seq > k.state.Seq {
+			k.lost += record.Seq - k.state.Seq
+		}
+		k.state.Seq = record.Seq + 1
+	} else if record.Usec <= k.state.Usec {
+		// dmesg 没有序号，时间戳相同的记录只处理第一次读到的
+		return false
+	}
+	k.state.Usec = record.Usec
+
+	eventType, attrs, ok := classifyKernelMessage(record.Message)
+	if !ok {
+		return false
+	}
+	k.state.Counts[eventType]++
+
+	message := record.Message
+	if len(message) > kernelLogMaxMessage {
+		message = message[:kernelLogMaxMessage]
+	}
+	event := models.KernelLogEvent{
+		Type:       eventType,
+		Time:       k.bootTime.Add(time.Duration(record.Usec) * time.Microsecond).UTC().Format("2006-01-02 15:04:05 MST"),
+		Priority:   record.Priority,
+		Message:    message,
+		Attributes: attrs,
+	}
+	k.recent = appendBounded(k.recent, event, kernelLogRecentEvents)
+
+	if k.emitted < kernelLogMaxEvents {
+		k.emitted++
+		eventAttrs := map[string]string{"kernel_time": event.Time}
+		for key, value := range attrs {
+			eventAttrs[key] = value
+		}
+		k.emit("kernel", eventType, message, eventAttrs)
+	}
+	return true
+}
+
+// parseKmsgRecord 解析 /dev/kmsg 记录
+//
+//	<优先级与设施>,<序号>,<微秒时间戳>,<标志>[,...];<消息>\n[ KEY=value\n...]
+func parseKmsgRecord(raw string) (kernelRecord, bool) {
+	header, message, ok := strings.Cut(raw, ";")
+	if !ok {
+		return kernelRecord{}, false
+	}
+	// 续行为设备等字典信息
+	message, _, _ = strings.Cut(message, "\n")
+
+	fields := strings.Split(header, ",")
+	if len(fields) < 3 {
+		return kernelRecord{}, false
+	}
+	prefix, err1 := strconv.Atoi(fields[0])
+	seq, err2 := strconv.ParseUint(fields[1], 10, 64)
+	usec, err3 := strconv.ParseInt(fields[2], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return kernelRecord{}, false
+	}
+	return kernelRecord{Seq: seq, Usec: usec, Priority: prefix & 7, Message: message}, true
+}
+
+// readDmesg 通过 dmesg -r 读取整个环形缓冲区
+func readDmesg(fn func(record kernelRecord)) error {
+	ctx, cancel := context.WithTimeout(context.Background(), dmesgTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "dmesg", "-r").Output()
+	if err != nil {
+		return fmt.Errorf("dmesg: %v", err)
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		m := dmesgLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		prefix, _ := strconv.Atoi(m[1])
+		sec, _ := strconv.ParseInt(m[2], 10, 64)
+		// 小数部分按微秒对齐
+		frac := (m[3] + "000000")[:6]
+		usec, _ := strconv.ParseInt(frac, 10, 64)
+		fn(kernelRecord{Usec: sec*1000000 + usec, Priority: prefix & 7, Message: m[4]})
+	}
+	return nil
+}
+
+// stateFile 读取位置文件路径
+func (k *KernelLogCollector) stateFile() string {
+	return filepath.Join(k.StateDir, "kernel-log-state.json")
+}
+
+// loadState 读取持久化位置，开机标识不同（已重启）时从头开始
+func (k *KernelLogCollector) loadState() *kernelLogState {
+	bootID := ""
+	if data, err := os.ReadFile(bootIDFile); err == nil {
+		bootID = strings.TrimSpace(string(data))
+	}
+	fresh := &kernelLogState{BootID: bootID, Usec: -1, Counts: make(map[string]uint64)}
+
+	data, err := os.ReadFile(k.stateFile())
+	if err != nil {
+		return fresh
+	}
+	state := &kernelLogState{}
+	if err := json.Unmarshal(data, state); err != nil || bootID == "" || state.BootID != bootID {
+		return fresh
+	}
+	if state.Counts == nil {
+		state.Counts = make(map[string]uint64)
+	}
+	return state
+}
+
+// saveState 将读取位置写入磁盘（先写临时文件再改名）
+func (k *KernelLogCollector) saveState() error {
+	data, err := json.Marshal(k.state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(k.StateDir, 0755); err != nil {
+		return err
+	}
+	tmp := k.stateFile() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, k.stateFile())
+}
@@ -0,0 +1,51 @@
+//go:build linux
+
+package collector
+
+import "syscall"
+
+// kmsgFile 内核日志环形缓冲区设备，每次 read 返回一条完整记录
+const kmsgFile = "/dev/kmsg"
+
+// kmsgReader /dev/kmsg 的非阻塞读取器
+// 直接使用文件描述符，避免 os.File 注册到网络轮询器后在没有新记录时阻塞
+type kmsgReader struct {
+	fd  int
+	buf []byte
+}
+
+// openKmsg 打开 /dev/kmsg，从环形缓冲区中最早的记录开始读取
+func openKmsg() (*kmsgReader, error) {
+	fd, err := syscall.Open(kmsgFile, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	// 单条记录不超过 8KB（含字典信息）
+	return &kmsgReader{fd: fd, buf: make([]byte, 16*1024)}, nil
+}
+
+// read 读取当前可用的全部记录
+// 读取过程中记录被覆盖时内核返回 EPIPE 并跳到下一条，丢失数量由序号间隔计算
+func (r *kmsgReader) read(fn func(record string)) error {
+	for {
+		n, err := syscall.Read(r.fd, r.buf)
+		switch err {
+		case nil:
+			if n <= 0 {
+				return nil
+			}
+			fn(string(r.buf[:n]))
+		case syscall.EAGAIN:
+			return nil
+		case syscall.EPIPE, syscall.EINTR:
+			continue
+		default:
+			return err
+		}
+	}
+}
+
+// close 关闭文件描述符
+func (r *kmsgReader) close() {
+	syscall.Close(r.fd)
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package collector
+
+import "errors"
+
+// kmsgFile 内核日志设备
+const kmsgFile = "/dev/kmsg"
+
+// kmsgReader 非 Linux 平台没有 /dev/kmsg
+type kmsgReader struct{}
+
+// openKmsg 非 Linux 平台总是失败，由调用方退回到 dmesg
+func openKmsg() (*kmsgReader, error) {
+	return nil, errors.New("not supported on this platform")
+}
+
+// read 不会被调用
+func (r *kmsgReader) read(fn func(record string)) error {
+	return nil
+}
+
+// close 不会被调用
+func (r *kmsgReader) close() {}
@@ -0,0 +1,221 @@
+package collector
+
+import (
+	"fmt"
+	"host-monitor-agent/models"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// 临时端口范围与为服务保留的端口
+const (
+	localPortRangeFile    = "/proc/sys/net/ipv4/ip_local_port_range"
+	localReservedPortFile = "/proc/sys/net/ipv4/ip_local_reserved_ports"
+)
+
+// ListenerCollector 监听套接字清单采集器
+type ListenerCollector struct {
+	eventBuffer
+
+	// 上一次采集到的监听端点，用于检测新增/消失
+	lastListeners map[string]models.ListenSocket
+	owners        socketOwnerCache
+}
+
+// Collect 采集所有监听中的 TCP 套接字与未连接的 UDP 套接字
+func (l *ListenerCollector) Collect() (interface{}, error) {
+	tcpEntries, source, err := readSockets(syscall.IPPROTO_TCP, 1<<tcpListen)
+	if err != nil {
+		return models.ListenerMetrics{}, err
+	}
+	// 未 connect 的 UDP 套接字处于 TCP_CLOSE 状态，connect 后为 TCP_ESTABLISHED
+	udpEntries, _, err := readSockets(syscall.IPPROTO_UDP, 1<<tcpClose|1<<tcpEstablished)
+	if err != nil {
+		udpEntries = nil
+	}
+	udpEntries = filterUDPServices(udpEntries)
+	ephemeral := ephemeralPorts()
+
+	inodes := make(map[uint32]bool, len(tcpEntries)+len(udpEntries))
+	for _, entry := range tcpEntries {
+		inodes[entry.Inode] = true
+	}
+	for _, entry := range udpEntries {
+		inodes[entry.Inode] = true
+	}
+	owners := l.owners.lookup(inodes)
+
+	metrics := models.ListenerMetrics{Source: source}
+	for _, entry := range tcpEntries {
+		socket := newListenSocket("tcp", entry, owners[entry.Inode])
+		socket.AcceptQueue = entry.RxQueue
+		if source == "netlink" {
+			socket.AcceptQueueMax = entry.TxQueue
+		}
+		metrics.Sockets = append(metrics.Sockets, socket)
+	}
+	for _, entry := range udpEntries {
+		socket := newListenSocket("udp", entry, owners[entry.Inode])
+		socket.Ephemeral = ephemeral(entry.LocalPort)
+		metrics.Sockets = append(metrics.Sockets, socket)
+	}
+
+	sort.Slice(metrics.Sockets, func(i, j int) bool {
+		a, b := metrics.Sockets[i], metrics.Sockets[j]
+		if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Address < b.Address
+	})
+	metrics.Total = len(metrics.Sockets)
+
+	l.detectChanges(metrics.Sockets)
+
+	return metrics, nil
+}
+
+// filterUDPServices 去掉已 connect 的客户端套接字（如 DNS 解析），对端地址非零即为客户端
+// 未 connect 的套接字无法区分服务与客户端，全部保留，由 Ephemeral 标记临时端口
+func filterUDPServices(entries []socketEntry) []socketEntry {
+	services := entries[:0]
+	for _, entry := range entries {
+		if entry.LocalPort == 0 || entry.RemotePort != 0 || (entry.RemoteIP != nil && !entry.RemoteIP.IsUnspecified()) {
+			continue
+		}
+		services = append(services, entry)
+	}
+	return services
+}
+
+// ephemeralPorts 返回判断端口是否由内核自动分配的函数
+// 位于临时端口范围且不在 ip_local_reserved_ports 中（如 WireGuard 51820）的端口视为临时端口
+func ephemeralPorts() func(uint16) bool {
+	low, high, ok := localPortRange()
+	if !ok {
+		return func(uint16) bool { return false }
+	}
+	reserved := reservedPorts()
+	return func(port uint16) bool {
+		return port >= low && port <= high && !reserved[port]
+	}
+}
+
+// localPortRange 读取临时端口范围，如 "32768	60999"
+func localPortRange() (uint16, uint16, bool) {
+	value, err := readSysfsString(localPortRangeFile)
+	if err != nil {
+		return 0, 0, false
+	}
+	fields := strings.Fields(value)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	low, err1 := strconv.ParseUint(fields[0], 10, 16)
+	high, err2 := strconv.ParseUint(fields[1], 10, 16)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return uint16(low), uint16(high), true
+}
+
+// reservedPorts 解析 ip_local_reserved_ports，如 "8080,51820-51822"
+func reservedPorts() map[uint16]bool {
+	ports := make(map[uint16]bool)
+	value, err := readSysfsString(localReservedPortFile)
+	if err != nil || value == "" {
+		return ports
+	}
+	for _, item := range strings.Split(value, ",") {
+		first, last, _ := strings.Cut(item, "-")
+		low, err := strconv.ParseUint(first, 10, 16)
+		if err != nil {
+			continue
+		}
+		high := low
+		if last != "" {
+			if high, err = strconv.ParseUint(last, 10, 16); err != nil {
+				continue
+			}
+		}
+		for port := low; port <= high; port++ {
+			ports[uint16(port)] = true
+		}
+	}
+	return ports
+}
+
+// detectChanges 对比上一次的监听端点，产生 listener_opened/listener_closed 事件
+// 首次采集只建立基线，不产生事件
+func (l *ListenerCollector) detectChanges(sockets []models.ListenSocket) {
+	current := make(map[string]models.ListenSocket, len(sockets))
+	for _, socket := range sockets {
+		current[listenerKey(socket)] = socket
+	}
+
+	if l.lastListeners != nil {
+		for key, socket := range current {
+			if _, ok := l.lastListeners[key]; !ok {
+				l.emit("listener", "listener_opened",
+					fmt.Sprintf("%s %s opened by %s", socket.Protocol, key[len(socket.Protocol)+1:], describeOwner(socket)),
+					listenerAttributes(socket))
+			}
+		}
+		for key, socket := range l.lastListeners {
+			if _, ok := current[key]; !ok {
+				l.emit("listener", "listener_closed",
+					fmt.Sprintf("%s %s closed (was %s)", socket.Protocol, key[len(socket.Protocol)+1:], describeOwner(socket)),
+					listenerAttributes(socket))
+			}
+		}
+	}
+
+	l.lastListeners = current
+}
+
+// newListenSocket 由套接字表记录构造监听套接字
+func newListenSocket(protocol string, entry socketEntry, owner procInfo) models.ListenSocket {
+	family := "ipv4"
+	if entry.Family == syscall.AF_INET6 {
+		family = "ipv6"
+	}
+
+	return models.ListenSocket{
+		Protocol: protocol,
+		Family:   family,
+		Address:  entry.LocalIP.String(),
+		Port:     entry.LocalPort,
+		PID:      owner.PID,
+		Process:  owner.Name,
+	}
+}
+
+// listenerKey 监听端点标识：协议 + 地址 + 端口（同端口多个 REUSEPORT 套接字视为一个）
+func listenerKey(socket models.ListenSocket) string {
+	return socket.Protocol + " " + net.JoinHostPort(socket.Address, strconv.Itoa(int(socket.Port)))
+}
+
+// describeOwner 格式化所属进程
+func describeOwner(socket models.ListenSocket) string {
+	if socket.PID == 0 {
+		return "unknown process"
+	}
+	return fmt.Sprintf("%s (pid %d)", socket.Process, socket.PID)
+}
+
+// listenerAttributes 事件附加属性
+func listenerAttributes(socket models.ListenSocket) map[string]string {
+	return map[string]string{
+		"protocol":  socket.Protocol,
+		"address":   socket.Address,
+		"port":      strconv.Itoa(int(socket.Port)),
+		"pid":       strconv.Itoa(int(socket.PID)),
+		"process":   socket.Process,
+		"ephemeral": strconv.FormatBool(socket.Ephemeral),
+	}
+}
@@ -0,0 +1,28 @@
+package collector
+
+import (
+	"net"
+	"testing"
+)
+
+func TestFilterUDPServices(t *testing.T) {
+	entries := []socketEntry{
+		// 未 connect 的服务套接字，即使端口位于临时端口范围也保留
+		{LocalIP: net.IPv4zero, LocalPort: 53, RemoteIP: net.IPv4zero},
+		{LocalIP: net.IPv4zero, LocalPort: 40000, RemoteIP: net.IPv4zero},
+		{LocalIP: net.IPv6zero, LocalPort: 51820, RemoteIP: net.IPv6zero},
+		// 已 connect 的客户端套接字
+		{LocalIP: net.IPv4(10, 0, 0, 5), LocalPort: 41234, RemoteIP: net.IPv4(10, 0, 0, 1), RemotePort: 53},
+		// 未绑定端口
+		{LocalIP: net.IPv4zero, RemoteIP: net.IPv4zero},
+	}
+
+	got := filterUDPServices(entries)
+	var ports []uint16
+	for _, entry := range got {
+		ports = append(ports, entry.LocalPort)
+	}
+	if len(ports) != 3 || ports[0] != 53 || ports[1] != 40000 || ports[2] != 51820 {
+		t.Errorf("filterUDPServices kept ports %v, want [53 40000 51820]", ports)
+	}
+}
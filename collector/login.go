@@ -0,0 +1,294 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"host-monitor-agent/models"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// utmp/wtmp/btmp 文件路径
+const (
+	utmpFile = "/var/run/utmp"
+	wtmpFile = "/var/log/wtmp"
+	btmpFile = "/var/log/btmp"
+)
+
+// utmpRecordSize glibc struct utmp 大小（64位与32位平台相同）
+const utmpRecordSize = 384
+
+// ut_type 取值
+const (
+	utRunLevel    = 1
+	utBootTime    = 2
+	utUserProcess = 7
+	utDeadProcess = 8
+)
+
+// loginHistorySize 保留的最近登录、失败登录与重启记录数
+const loginHistorySize = 20
+
+// loginInitialRecords 首次采集时从 wtmp/btmp 末尾回读的记录数
+const loginInitialRecords = 1000
+
+// utmpRecord 解析后的 utmp 记录
+type utmpRecord struct {
+	Type int16
+	PID  int32
+	Line string
+	User string
+	Host string
+	Time time.Time
+}
+
+// utmpTail 增量读取 wtmp/btmp，记录上次读取位置
+type utmpTail struct {
+	path    string
+	info    os.FileInfo
+	offset  int64
+	skipped int64 // 首次读取时跳过的早期记录数
+}
+
+// LoginCollector 登录会话与登录历史采集器
+type LoginCollector struct {
+	wtmp *utmpTail
+	btmp *utmpTail
+
+	logins   []models.LoginRecord // 新的在后
+	reboots  []models.BootRecord  // 新的在后
+	failures []models.LoginRecord // 新的在后
+	lastBoot time.Time
+
+	failedAttempts uint64 // 启动时 btmp 中的记录数加上之后读到的记录数，btmp 轮转后继续累加
+}
+
+// Collect 采集当前会话、最近登录与失败登录
+func (l *LoginCollector) Collect() (interface{}, error) {
+	if l.wtmp == nil {
+		l.wtmp = &utmpTail{path: wtmpFile}
+		l.btmp = &utmpTail{path: btmpFile}
+	}
+
+	metrics := models.LoginMetrics{
+		Sessions: []models.LoginSession{},
+	}
+
+	if records, err := readUtmpFile(utmpFile); err == nil {
+		metrics.Available = true
+		users := make(map[string]bool)
+		for _, record := range records {
+			if record.Type == utBootTime && record.Time.After(l.lastBoot) {
+				l.lastBoot = record.Time
+			}
+			if record.Type != utUserProcess || record.User == "" || !processExists(record.PID) {
+				continue
+			}
+			users[record.User] = true
+			metrics.Sessions = append(metrics.Sessions, models.LoginSession{
+				User:        record.User,
+				TTY:         record.Line,
+				Host:        record.Host,
+				PID:         record.PID,
+				LoginTime:   formatLoginTime(record.Time),
+				IdleSeconds: ttyIdleSeconds(record.Line),
+			})
+		}
+		metrics.Users = len(users)
+	}
+
+	if records, err := l.wtmp.read(); err == nil {
+		for _, record := range records {
+			l.observeWtmp(record)
+		}
+	}
+
+	if records, err := l.btmp.read(); err == nil {
+		metrics.BtmpAvailable = true
+		l.failedAttempts += uint64(l.btmp.skipped) + uint64(len(records))
+		l.btmp.skipped = 0
+		for _, record := range records {
+			if record.User == "" && record.Host == "" {
+				continue
+			}
+			l.failures = appendBounded(l.failures, models.LoginRecord{
+				User:      record.User,
+				TTY:       record.Line,
+				Host:      record.Host,
+				LoginTime: formatLoginTime(record.Time),
+			}, loginHistorySize)
+		}
+	}
+
+	if !l.lastBoot.IsZero() {
+		metrics.LastBoot = formatLoginTime(l.lastBoot)
+	}
+	metrics.RecentLogins = reversed(l.logins)
+	metrics.RecentReboots = reversed(l.reboots)
+	metrics.RecentFailures = reversed(l.failures)
+	metrics.FailedAttempts = l.failedAttempts
+
+	return metrics, nil
+}
+
+// observeWtmp 处理一条 wtmp 记录：登录、登出与重启
+func (l *LoginCollector) observeWtmp(record utmpRecord) {
+	switch {
+	case record.Type == utUserProcess && record.User != "":
+		l.logins = appendBounded(l.logins, models.LoginRecord{
+			User:      record.User,
+			TTY:       record.Line,
+			Host:      record.Host,
+			LoginTime: formatLoginTime(record.Time),
+		}, loginHistorySize)
+
+	case record.Type == utDeadProcess && record.Line != "":
+		// 登出记录只有终端名，匹配该终端最近一次未登出的登录
+		for i := len(l.logins) - 1; i >= 0; i-- {
+			if l.logins[i].TTY == record.Line && l.logins[i].LogoutTime == "" {
+				closeLogin(&l.logins[i], record.Time)
+				break
+			}
+		}
+
+	case record.Type == utBootTime || (record.Type == utRunLevel && record.User == "shutdown"):
+		kind := "reboot"
+		if record.Type == utRunLevel {
+			kind = "shutdown"
+		} else if record.Time.After(l.lastBoot) {
+			l.lastBoot = record.Time
+		}
+		l.reboots = appendBounded(l.reboots, models.BootRecord{
+			Type:   kind,
+			Time:   formatLoginTime(record.Time),
+			Kernel: record.Host,
+		}, loginHistorySize)
+		// 重启或关机结束所有仍在登录的会话
+		for i := range l.logins {
+			if l.logins[i].LogoutTime == "" {
+				closeLogin(&l.logins[i], record.Time)
+			}
+		}
+	}
+}
+
+// closeLogin 记录登出时间与会话时长
+func closeLogin(login *models.LoginRecord, logout time.Time) {
+	login.LogoutTime = formatLoginTime(logout)
+	if start, err := time.Parse("2006-01-02 15:04:05 MST", login.LoginTime); err == nil && logout.After(start) {
+		login.DurationSeconds = int64(logout.Sub(start).Seconds())
+	}
+}
+
+// read 读取自上次调用以来追加的完整记录
+// 文件被轮转（inode 变化）或截断时从头读取，首次读取只回读末尾的记录
+func (t *utmpTail) read() ([]utmpRecord, error) {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case t.info == nil:
+		t.offset = info.Size() - loginInitialRecords*utmpRecordSize
+		if t.offset < 0 {
+			t.offset = 0
+		}
+		t.offset -= t.offset % utmpRecordSize
+		t.skipped = t.offset / utmpRecordSize
+	case !os.SameFile(info, t.info) || info.Size() < t.offset:
+		t.offset = 0
+	}
+	t.info = info
+
+	size := info.Size() - info.Size()%utmpRecordSize
+	if size <= t.offset {
+		return nil, nil
+	}
+
+	data := make([]byte, size-t.offset)
+	if _, err := file.ReadAt(data, t.offset); err != nil && err != io.EOF {
+		return nil, err
+	}
+	t.offset = size
+
+	return parseUtmpRecords(data), nil
+}
+
+// readUtmpFile 读取整个 utmp 文件
+func readUtmpFile(path string) ([]utmpRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseUtmpRecords(data), nil
+}
+
+// parseUtmpRecords 按 glibc struct utmp 布局解析记录（本机字节序）
+//
+//	0 ut_type int16, 4 ut_pid int32, 8 ut_line[32], 40 ut_id[4], 44 ut_user[32],
+//	76 ut_host[256], 332 ut_exit, 336 ut_session, 340 ut_tv{int32,int32}, 348 ut_addr_v6[16]
+func parseUtmpRecords(data []byte) []utmpRecord {
+	records := make([]utmpRecord, 0, len(data)/utmpRecordSize)
+	for off := 0; off+utmpRecordSize <= len(data); off += utmpRecordSize {
+		raw := data[off : off+utmpRecordSize]
+		record := utmpRecord{
+			Type: int16(binary.NativeEndian.Uint16(raw[0:2])),
+			PID:  int32(binary.NativeEndian.Uint32(raw[4:8])),
+			Line: cString(raw[8:40]),
+			User: cString(raw[44:76]),
+			Host: cString(raw[76:332]),
+			Time: time.Unix(int64(int32(binary.NativeEndian.Uint32(raw[340:344]))),
+				int64(int32(binary.NativeEndian.Uint32(raw[344:348])))*1000),
+		}
+		if record.Host == "" {
+			record.Host = utmpAddr(raw[348:364])
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// utmpAddr 将 ut_addr_v6 转换为地址字符串，全零时返回空
+// IPv4 地址只占用第一个 32 位字
+func utmpAddr(raw []byte) string {
+	if bytes.Count(raw, []byte{0}) == len(raw) {
+		return ""
+	}
+	if bytes.Count(raw[4:], []byte{0}) == len(raw)-4 {
+		return net.IP(raw[:4]).String()
+	}
+	return net.IP(raw).String()
+}
+
+// cString 截取 C 字符串中 NUL 之前的部分
+func cString(raw []byte) string {
+	if i := bytes.IndexByte(raw, 0); i >= 0 {
+		raw = raw[:i]
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// processExists 过滤进程已退出但未清理的 utmp 记录
+func processExists(pid int32) bool {
+	if pid <= 0 {
+		return true
+	}
+	_, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
+	return err == nil
+}
+
+// formatLoginTime 统一的时间格式
+func formatLoginTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05 MST")
+}
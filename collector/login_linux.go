@@ -0,0 +1,29 @@
+//go:build linux
+
+package collector
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// ttyIdleSeconds 以终端设备的访问时间计算空闲时长（与 w 命令一致）
+func ttyIdleSeconds(line string) int64 {
+	if line == "" {
+		return 0
+	}
+	info, err := os.Stat("/dev/" + line)
+	if err != nil {
+		return 0
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0
+	}
+	idle := time.Since(time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec)))
+	if idle < 0 {
+		return 0
+	}
+	return int64(idle.Seconds())
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package collector
+
+// ttyIdleSeconds 非 Linux 平台不计算终端空闲时长
+func ttyIdleSeconds(line string) int64 {
+	return 0
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"host-monitor-agent/models"
+)
+
+// btmpRecords 构造 n 条失败登录记录
+func btmpRecords(n int, user string) []byte {
+	data := make([]byte, n*utmpRecordSize)
+	for i := 0; i < n; i++ {
+		record := data[i*utmpRecordSize:]
+		binary.NativeEndian.PutUint16(record[0:2], 6) // LOGIN_PROCESS
+		copy(record[8:], "ssh:notty")
+		copy(record[44:], user)
+		copy(record[76:], "198.51.100.7")
+		binary.NativeEndian.PutUint32(record[340:344], 1760000000)
+	}
+	return data
+}
+
+func TestLoginFailedAttemptsAcrossRotation(t *testing.T) {
+	dir := t.TempDir()
+	btmp := filepath.Join(dir, "btmp")
+	writeBtmp := func(data []byte, flag int) {
+		t.Helper()
+		file, err := os.OpenFile(btmp, flag|os.O_WRONLY|os.O_CREATE, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if _, err := file.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := &LoginCollector{
+		wtmp: &utmpTail{path: filepath.Join(dir, "wtmp")},
+		btmp: &utmpTail{path: btmp},
+	}
+	failed := func() uint64 {
+		t.Helper()
+		result, err := l.Collect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result.(models.LoginMetrics).FailedAttempts
+	}
+
+	// 首次只回读末尾的记录，更早的记录同样计入
+	writeBtmp(btmpRecords(loginInitialRecords+3, "admin"), os.O_TRUNC)
+	if got := failed(); got != loginInitialRecords+3 {
+		t.Errorf("initial = %d, want %d", got, loginInitialRecords+3)
+	}
+	writeBtmp(btmpRecords(2, "oracle"), os.O_APPEND)
+	if got := failed(); got != loginInitialRecords+5 {
+		t.Errorf("after append = %d, want %d", got, loginInitialRecords+5)
+	}
+
+	// 轮转后新文件从零开始，计数继续累加
+	if err := os.Rename(btmp, btmp+".1"); err != nil {
+		t.Fatal(err)
+	}
+	writeBtmp(btmpRecords(1, "test"), os.O_TRUNC)
+	if got := failed(); got != loginInitialRecords+6 {
+		t.Errorf("after rotation = %d, want %d", got, loginInitialRecords+6)
+	}
+}
@@ -0,0 +1,129 @@
+package collector
+
+import (
+	"bufio"
+	"fmt"
+	"host-monitor-agent/models"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NetStatCollector 内核协议计数器采集器
+type NetStatCollector struct {
+	// 上一次采集的计数器，用于计算速率
+	last     *models.NetStatMetrics
+	lastTime time.Time
+}
+
+// Collect 采集 /proc/net/snmp 与 /proc/net/netstat 中的 TCP/UDP 计数器
+func (n *NetStatCollector) Collect() (interface{}, error) {
+	snmp, err := parseProcNetStat("/proc/net/snmp")
+	if err != nil {
+		return models.NetStatMetrics{}, err
+	}
+
+	// TcpExt 计数器读取失败时仅缺少扩展字段
+	ext, err := parseProcNetStat("/proc/net/netstat")
+	if err != nil {
+		ext = map[string]map[string]uint64{}
+	}
+
+	tcp := snmp["Tcp"]
+	tcpExt := ext["TcpExt"]
+	udp := snmp["Udp"]
+
+	metrics := models.NetStatMetrics{
+		TCP: models.TCPCounters{
+			ActiveOpens:     tcp["ActiveOpens"],
+			PassiveOpens:    tcp["PassiveOpens"],
+			AttemptFails:    tcp["AttemptFails"],
+			EstabResets:     tcp["EstabResets"],
+			OutRsts:         tcp["OutRsts"],
+			InSegs:          tcp["InSegs"],
+			OutSegs:         tcp["OutSegs"],
+			RetransSegs:     tcp["RetransSegs"],
+			InErrs:          tcp["InErrs"],
+			ListenOverflows: tcpExt["ListenOverflows"],
+			ListenDrops:     tcpExt["ListenDrops"],
+			SyncookiesSent:  tcpExt["SyncookiesSent"],
+			Timeouts:        tcpExt["TCPTimeouts"],
+		},
+		UDP: models.UDPCounters{
+			InDatagrams:  udp["InDatagrams"],
+			OutDatagrams: udp["OutDatagrams"],
+			NoPorts:      udp["NoPorts"],
+			InErrors:     udp["InErrors"],
+			RcvbufErrors: udp["RcvbufErrors"],
+			SndbufErrors: udp["SndbufErrors"],
+		},
+	}
+
+	now := time.Now()
+	if n.last != nil {
+		elapsed := now.Sub(n.lastTime).Seconds()
+		cur, prev := metrics.TCP, n.last.TCP
+		metrics.Rates = models.NetStatRates{
+			ActiveOpensPerSec:     roundRate(counterRate(cur.ActiveOpens, prev.ActiveOpens, elapsed)),
+			PassiveOpensPerSec:    roundRate(counterRate(cur.PassiveOpens, prev.PassiveOpens, elapsed)),
+			EstabResetsPerSec:     roundRate(counterRate(cur.EstabResets, prev.EstabResets, elapsed)),
+			OutRstsPerSec:         roundRate(counterRate(cur.OutRsts, prev.OutRsts, elapsed)),
+			RetransSegsPerSec:     roundRate(counterRate(cur.RetransSegs, prev.RetransSegs, elapsed)),
+			ListenOverflowsPerSec: roundRate(counterRate(cur.ListenOverflows, prev.ListenOverflows, elapsed)),
+			ListenDropsPerSec:     roundRate(counterRate(cur.ListenDrops, prev.ListenDrops, elapsed)),
+			SyncookiesSentPerSec:  roundRate(counterRate(cur.SyncookiesSent, prev.SyncookiesSent, elapsed)),
+			TimeoutsPerSec:        roundRate(counterRate(cur.Timeouts, prev.Timeouts, elapsed)),
+			UDPInErrorsPerSec:     roundRate(counterRate(metrics.UDP.InErrors, n.last.UDP.InErrors, elapsed)),
+			UDPRcvbufErrorsPerSec: roundRate(counterRate(metrics.UDP.RcvbufErrors, n.last.UDP.RcvbufErrors, elapsed)),
+		}
+	}
+
+	last := metrics
+	n.last = &last
+	n.lastTime = now
+
+	return metrics, nil
+}
+
+// parseProcNetStat 解析 /proc/net/snmp 格式的文件
+// 文件由成对的行组成：第一行为 "Proto: 名称..."，第二行为 "Proto: 数值..."
+// 返回 协议 -> 计数器名 -> 值，负值（如 MaxConn=-1）被忽略
+func parseProcNetStat(path string) (map[string]map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make(map[string]map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		header := strings.Fields(scanner.Text())
+		if !scanner.Scan() {
+			break
+		}
+		values := strings.Fields(scanner.Text())
+
+		if len(header) == 0 || len(header) != len(values) || header[0] != values[0] {
+			return nil, fmt.Errorf("malformed %s: mismatched header %q", path, strings.Join(header, " "))
+		}
+
+		proto := strings.TrimSuffix(header[0], ":")
+		counters := make(map[string]uint64, len(header)-1)
+		for i := 1; i < len(header); i++ {
+			if v, err := strconv.ParseUint(values[i], 10, 64); err == nil {
+				counters[header[i]] = v
+			}
+		}
+		result[proto] = counters
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
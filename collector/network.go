@@ -2,12 +2,22 @@ package collector
 
 import (
 	"host-monitor-agent/models"
+	"math"
+	"path/filepath"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/net"
 )
 
+// sysClassNet 网络接口 sysfs 目录
+const sysClassNet = "/sys/class/net"
+
 // NetworkCollector 网络流量采集器
-type NetworkCollector struct{}
+type NetworkCollector struct {
+	// 上一次采集的计数器，用于计算速率
+	lastCounters map[string]net.IOCountersStat
+	lastTime     time.Time
+}
 
 // Collect 采集网络流量指标
 func (n *NetworkCollector) Collect() (interface{}, error) {
@@ -16,19 +26,86 @@ func (n *NetworkCollector) Collect() (interface{}, error) {
 		return []models.NetworkMetrics{}, err
 	}
 
+	now := time.Now()
+	elapsed := now.Sub(n.lastTime).Seconds()
+	current := make(map[string]net.IOCountersStat, len(ioCounters))
+
 	var networkMetrics []models.NetworkMetrics
 	for _, counter := range ioCounters {
 		// 跳过 loopback 接口
 		if counter.Name == "lo" {
 			continue
 		}
+		current[counter.Name] = counter
 
-		networkMetrics = append(networkMetrics, models.NetworkMetrics{
-			Interface: counter.Name,
-			BytesSent: counter.BytesSent,
-			BytesRecv: counter.BytesRecv,
-		})
+		metrics := models.NetworkMetrics{
+			Interface:   counter.Name,
+			BytesSent:   counter.BytesSent,
+			BytesRecv:   counter.BytesRecv,
+			PacketsSent: counter.PacketsSent,
+			PacketsRecv: counter.PacketsRecv,
+			ErrorsIn:    counter.Errin,
+			ErrorsOut:   counter.Errout,
+			DropsIn:     counter.Dropin,
+			DropsOut:    counter.Dropout,
+			FifoIn:      counter.Fifoin,
+			FifoOut:     counter.Fifoout,
+			SpeedMbps:   -1,
+		}
+		readLinkState(counter.Name, &metrics)
+
+		if prev, ok := n.lastCounters[counter.Name]; ok {
+			metrics.Rates = models.NetRate{
+				BytesSentPerSec:   roundRate(counterRate(counter.BytesSent, prev.BytesSent, elapsed)),
+				BytesRecvPerSec:   roundRate(counterRate(counter.BytesRecv, prev.BytesRecv, elapsed)),
+				PacketsSentPerSec: roundRate(counterRate(counter.PacketsSent, prev.PacketsSent, elapsed)),
+				PacketsRecvPerSec: roundRate(counterRate(counter.PacketsRecv, prev.PacketsRecv, elapsed)),
+				ErrorsInPerSec:    roundRate(counterRate(counter.Errin, prev.Errin, elapsed)),
+				ErrorsOutPerSec:   roundRate(counterRate(counter.Errout, prev.Errout, elapsed)),
+				DropsInPerSec:     roundRate(counterRate(counter.Dropin, prev.Dropin, elapsed)),
+				DropsOutPerSec:    roundRate(counterRate(counter.Dropout, prev.Dropout, elapsed)),
+			}
+		}
+
+		networkMetrics = append(networkMetrics, metrics)
 	}
 
+	n.lastCounters = current
+	n.lastTime = now
+
 	return networkMetrics, nil
-}
\ No newline at end of file
+}
+
+// readLinkState 从 /sys/class/net/<iface> 读取链路属性
+// 非 Linux 平台或虚拟接口缺少的属性保持默认值
+func readLinkState(name string, metrics *models.NetworkMetrics) {
+	dir := filepath.Join(sysClassNet, name)
+
+	if mac, err := readSysfsString(filepath.Join(dir, "address")); err == nil {
+		metrics.MACAddress = mac
+	}
+	if state, err := readSysfsString(filepath.Join(dir, "operstate")); err == nil {
+		metrics.OperState = state
+	}
+	// 链路未连通时读取 speed/duplex 会返回 EINVAL
+	if speed, err := readSysfsInt(filepath.Join(dir, "speed")); err == nil {
+		metrics.SpeedMbps = speed
+	}
+	if duplex, err := readSysfsString(filepath.Join(dir, "duplex")); err == nil {
+		metrics.Duplex = duplex
+	}
+	if mtu, err := readSysfsUint(filepath.Join(dir, "mtu")); err == nil {
+		metrics.MTU = mtu
+	}
+	if changes, err := readSysfsUint(filepath.Join(dir, "carrier_changes")); err == nil {
+		metrics.CarrierChanges = changes
+	}
+	if multicast, err := readSysfsUint(filepath.Join(dir, "statistics", "multicast")); err == nil {
+		metrics.Multicast = multicast
+	}
+}
+
+// roundRate 速率保留1位小数
+func roundRate(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,222 @@
+package collector
+
+import (
+	"bufio"
+	"host-monitor-agent/models"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mountStatsFile NFS 客户端按挂载点的统计
+const mountStatsFile = "/proc/self/mountstats"
+
+// nfsOpStats per-op statistics 中一行的计数
+// ops transmissions major_timeouts bytes_sent bytes_recv queue_ms rtt_ms execute_ms [errors]
+type nfsOpStats struct {
+	name                                      string
+	ops, trans, timeouts, rttMs, execMs, errs uint64
+}
+
+// nfsMount 一个 NFS 挂载点的统计
+type nfsMount struct {
+	device, mountPoint, fsType, version string
+	readBytes, writeBytes               uint64
+	ops                                 []nfsOpStats
+}
+
+// nfsTotals 用于计算速率的累计值
+type nfsTotals struct {
+	ops, retrans, rttMs uint64
+}
+
+// NFSCollector NFS 客户端统计采集器
+type NFSCollector struct {
+	last     map[string]nfsTotals
+	lastTime time.Time
+}
+
+// Collect 解析 /proc/self/mountstats，计算每个挂载点的操作数、RTT 与重传
+func (n *NFSCollector) Collect() (interface{}, error) {
+	mounts, err := readMountStats(mountStatsFile)
+	if err != nil {
+		return []models.NFSMountMetrics{}, err
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(n.lastTime).Seconds()
+	current := make(map[string]nfsTotals, len(mounts))
+
+	result := []models.NFSMountMetrics{}
+	for _, mount := range mounts {
+		metrics := models.NFSMountMetrics{
+			MountPoint: mount.mountPoint,
+			Device:     mount.device,
+			Server:     mountServer(mount.fsType, mount.device),
+			FSType:     mount.fsType,
+			Version:    mount.version,
+			ReadBytes:  mount.readBytes,
+			WriteBytes: mount.writeBytes,
+			Operations: []models.NFSOpStat{},
+		}
+
+		var rttMs, execMs uint64
+		for _, op := range mount.ops {
+			metrics.Ops += op.ops
+			metrics.MajorTimeouts += op.timeouts
+			metrics.Errors += op.errs
+			rttMs += op.rttMs
+			execMs += op.execMs
+			// 传输次数超过操作数的部分即重传
+			retrans := uint64(0)
+			if op.trans > op.ops {
+				retrans = op.trans - op.ops
+			}
+			metrics.Retransmits += retrans
+
+			if op.ops == 0 {
+				continue
+			}
+			metrics.Operations = append(metrics.Operations, models.NFSOpStat{
+				Op:            op.name,
+				Ops:           op.ops,
+				Retransmits:   retrans,
+				MajorTimeouts: op.timeouts,
+				Errors:        op.errs,
+				AvgRTTMs:      averageMs(op.rttMs, op.ops),
+				AvgExecMs:     averageMs(op.execMs, op.ops),
+			})
+		}
+		sort.Slice(metrics.Operations, func(i, j int) bool {
+			return metrics.Operations[i].Ops > metrics.Operations[j].Ops
+		})
+		metrics.AvgRTTMs = averageMs(rttMs, metrics.Ops)
+		metrics.AvgExecMs = averageMs(execMs, metrics.Ops)
+
+		totals := nfsTotals{ops: metrics.Ops, retrans: metrics.Retransmits, rttMs: rttMs}
+		current[mount.mountPoint] = totals
+		if prev, ok := n.last[mount.mountPoint]; ok {
+			metrics.OpsPerSec = roundRate(counterRate(totals.ops, prev.ops, elapsed))
+			metrics.RetransPerSec = roundRate(counterRate(totals.retrans, prev.retrans, elapsed))
+			if totals.ops > prev.ops && totals.rttMs >= prev.rttMs {
+				metrics.IntervalRTTMs = averageMs(totals.rttMs-prev.rttMs, totals.ops-prev.ops)
+			}
+		}
+
+		result = append(result, metrics)
+	}
+
+	n.last = current
+	n.lastTime = now
+
+	return result, nil
+}
+
+// averageMs 累计毫秒数按次数求平均，保留2位小数
+func averageMs(totalMs, count uint64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return math.Round(float64(totalMs)/float64(count)*100) / 100
+}
+
+// readMountStats 解析 mountstats 中的 NFS 挂载点
+//
+//	device server:/export mounted on /mnt with fstype nfs4 statvers=1.1
+//		opts:	rw,vers=4.2,...
+//		bytes:	normalread normalwrite directread directwrite serverread serverwrite readpages writepages
+//		per-op statistics
+//		        READ: 10 10 0 1440 41200 2 30 33 0
+func readMountStats(path string) ([]*nfsMount, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var mounts []*nfsMount
+	var mount *nfsMount
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "device ") {
+			mount = nil
+			fields := strings.Fields(line)
+			// device <dev> mounted on <mountpoint> with fstype <type> ...
+			if len(fields) >= 8 && fields[2] == "mounted" && fields[5] == "with" &&
+				(fields[7] == "nfs" || fields[7] == "nfs4") {
+				mount = &nfsMount{
+					device:     unescapeMountField(fields[1]),
+					mountPoint: unescapeMountField(fields[4]),
+					fsType:     fields[7],
+				}
+				mounts = append(mounts, mount)
+			}
+			continue
+		}
+		if mount == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		values := strings.Fields(value)
+		switch key {
+		case "opts":
+			for _, option := range strings.Split(strings.TrimSpace(value), ",") {
+				if strings.HasPrefix(option, "vers=") {
+					mount.version = strings.TrimPrefix(option, "vers=")
+				}
+			}
+		case "bytes":
+			if len(values) >= 6 {
+				mount.readBytes, _ = strconv.ParseUint(values[4], 10, 64)
+				mount.writeBytes, _ = strconv.ParseUint(values[5], 10, 64)
+			}
+		default:
+			// per-op 行：操作名为大写（含下划线），其后至少 8 个计数
+			if len(values) < 8 || key != strings.ToUpper(key) {
+				continue
+			}
+			counts := make([]uint64, 9)
+			for i := 0; i < len(values) && i < len(counts); i++ {
+				counts[i], _ = strconv.ParseUint(values[i], 10, 64)
+			}
+			mount.ops = append(mount.ops, nfsOpStats{
+				name:     key,
+				ops:      counts[0],
+				trans:    counts[1],
+				timeouts: counts[2],
+				rttMs:    counts[6],
+				execMs:   counts[7],
+				errs:     counts[8],
+			})
+		}
+	}
+	return mounts, scanner.Err()
+}
+
+// unescapeMountField 还原挂载信息中的八进制转义（如空格为 \040）
+func unescapeMountField(field string) string {
+	if !strings.Contains(field, `\`) {
+		return field
+	}
+	var b strings.Builder
+	for i := 0; i < len(field); i++ {
+		if field[i] == '\\' && i+3 < len(field) {
+			if v, err := strconv.ParseUint(field[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(field[i])
+	}
+	return b.String()
+}
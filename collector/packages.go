@@ -0,0 +1,391 @@
+package collector
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"host-monitor-agent/models"
+	"host-monitor-agent/rpmdb"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// dpkgStatusFile dpkg 软件包状态数据库
+const dpkgStatusFile = "/var/lib/dpkg/status"
+
+// rpmDBDirs rpm 数据库目录，较新的发行版迁移到 /usr/lib/sysimage/rpm 并保留符号链接
+var rpmDBDirs = []string{"/var/lib/rpm", "/usr/lib/sysimage/rpm"}
+
+// rpmQueryTimeout rpm -qa 的超时时间，大型数据库查询可能需要数秒
+// 查询在后台执行，不阻塞采集
+const rpmQueryTimeout = 60 * time.Second
+
+// rpmQueryFormat rpm -qa 输出格式，与直接读取数据库的版本格式一致
+const rpmQueryFormat = `%{NAME}\t%|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE}\t%{ARCH}\n`
+
+// packageRecentChanges 指标中保留的最近变化数
+const packageRecentChanges = 50
+
+// packageMaxEvents 单次刷新最多产生的逐包事件数，超出部分汇总为一条事件
+// 避免发行版大版本升级时大量事件挤占事件缓存
+const packageMaxEvents = 100
+
+// PackageCollector 已安装软件包清单采集器
+// 数据库文件未变化时复用上一次的结果
+type PackageCollector struct {
+	eventBuffer
+	signature string
+	last      models.PackageMetrics
+	versions  map[string][]string // name/arch -> 已安装版本（rpm 允许同时安装多个版本，如 kernel）
+	recent    []models.PackageChange
+
+	installed uint64
+	upgraded  uint64
+	removed   uint64
+
+	rpmQuery chan rpmQueryResult // 正在执行的后台 rpm -qa
+}
+
+// rpmQueryResult 后台 rpm -qa 的查询结果
+type rpmQueryResult struct {
+	packages  []models.InstalledPackage
+	signature string // 查询开始时的数据库文件签名
+	err       error
+}
+
+// Collect 读取软件包数据库，与上一次对比产生安装、升级、删除事件
+func (p *PackageCollector) Collect() (interface{}, error) {
+	manager, source, files := detectPackageManager()
+	if manager == "" {
+		return models.PackageMetrics{
+			RecentChanges: []models.PackageChange{},
+			Packages:      []models.InstalledPackage{},
+		}, nil
+	}
+
+	signature := fileSignature(files)
+	if p.signature != "" && signature == p.signature {
+		return p.last, nil
+	}
+
+	metrics := models.PackageMetrics{
+		Available:     true,
+		Manager:       manager,
+		Source:        source,
+		LastRefresh:   time.Now().UTC().Format("2006-01-02 15:04:05 MST"),
+		RecentChanges: []models.PackageChange{},
+		Packages:      []models.InstalledPackage{},
+	}
+
+	var packages []models.InstalledPackage
+	var err error
+	if manager == "dpkg" {
+		packages, err = readDpkgStatus(dpkgStatusFile)
+	} else if packages, err = readRPMDatabase(files[0], source); err != nil {
+		// 格式不支持或读取失败时退回到 rpm -qa，查询完成前沿用上一次的结果
+		result, done := p.queryRPMAsync(filepath.Dir(files[0]), signature)
+		if !done {
+			return p.pending(manager), nil
+		}
+		// 查询期间数据库可能再次变化，以开始时的签名为准，下一次采集会重新查询
+		packages, signature, err = result.packages, result.signature, result.err
+		metrics.Source = "rpm-qa"
+	}
+	if err != nil {
+		// 保留上一次的清单与基线，下一次采集重试
+		metrics.Error = err.Error()
+		if p.versions != nil {
+			metrics.Total = p.last.Total
+			metrics.Packages = p.last.Packages
+			metrics.RecentChanges = p.last.RecentChanges
+		}
+		metrics.Installed, metrics.Upgraded, metrics.Removed = p.installed, p.upgraded, p.removed
+		return metrics, nil
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		if packages[i].Name != packages[j].Name {
+			return packages[i].Name < packages[j].Name
+		}
+		if packages[i].Arch != packages[j].Arch {
+			return packages[i].Arch < packages[j].Arch
+		}
+		return packages[i].Version < packages[j].Version
+	})
+
+	versions := make(map[string][]string, len(packages))
+	for _, pkg := range packages {
+		key := pkg.Name + "/" + pkg.Arch
+		versions[key] = append(versions[key], pkg.Version)
+	}
+	if p.versions != nil {
+		p.detectChanges(p.versions, versions)
+	}
+	p.versions = versions
+	p.signature = signature
+
+	metrics.Total = len(packages)
+	metrics.Packages = packages
+	metrics.Installed, metrics.Upgraded, metrics.Removed = p.installed, p.upgraded, p.removed
+	metrics.RecentChanges = reversed(p.recent)
+
+	p.last = metrics
+	return metrics, nil
+}
+
+// detectChanges 按 name/arch 对比版本集合
+// 只增加版本为安装，只减少版本为删除，同时增减为升级（或降级）
+func (p *PackageCollector) detectChanges(last, current map[string][]string) {
+	keys := make([]string, 0, len(current))
+	for key := range current {
+		keys = append(keys, key)
+	}
+	for key := range last {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	now := time.Now().UTC().Format("2006-01-02 15:04:05 MST")
+	var changes []models.PackageChange
+	for _, key := range keys {
+		added := versionsDiff(current[key], last[key])
+		removed := versionsDiff(last[key], current[key])
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		name, arch, _ := strings.Cut(key, "/")
+		switch {
+		case len(removed) == 0:
+			for _, version := range added {
+				changes = append(changes, models.PackageChange{Name: name, Arch: arch, Change: "installed", NewVersion: version, Time: now})
+			}
+		case len(added) == 0:
+			for _, version := range removed {
+				changes = append(changes, models.PackageChange{Name: name, Arch: arch, Change: "removed", OldVersion: version, Time: now})
+			}
+		default:
+			changes = append(changes, models.PackageChange{
+				Name:       name,
+				Arch:       arch,
+				Change:     "upgraded",
+				OldVersion: strings.Join(removed, ", "),
+				NewVersion: strings.Join(added, ", "),
+				Time:       now,
+			})
+		}
+	}
+
+	for i, change := range changes {
+		switch change.Change {
+		case "installed":
+			p.installed++
+		case "upgraded":
+			p.upgraded++
+		case "removed":
+			p.removed++
+		}
+		p.recent = appendBounded(p.recent, change, packageRecentChanges)
+
+		if i < packageMaxEvents {
+			p.emitChange(change)
+		}
+	}
+	if len(changes) > packageMaxEvents {
+		p.emit("packages", "package_changes_truncated",
+			fmt.Sprintf("%d more package changes not reported individually", len(changes)-packageMaxEvents),
+			map[string]string{"total": fmt.Sprint(len(changes))})
+	}
+}
+
+// emitChange 发出 package_installed/package_upgraded/package_removed 事件
+func (p *PackageCollector) emitChange(change models.PackageChange) {
+	var message string
+	switch change.Change {
+	case "installed":
+		message = fmt.Sprintf("package %s (%s) %s installed", change.Name, change.Arch, change.NewVersion)
+	case "upgraded":
+		message = fmt.Sprintf("package %s (%s) upgraded from %s to %s", change.Name, change.Arch, change.OldVersion, change.NewVersion)
+	case "removed":
+		message = fmt.Sprintf("package %s (%s) %s removed", change.Name, change.Arch, change.OldVersion)
+	}
+	p.emit("packages", "package_"+change.Change, message, map[string]string{
+		"name":        change.Name,
+		"arch":        change.Arch,
+		"old_version": change.OldVersion,
+		"new_version": change.NewVersion,
+	})
+}
+
+// versionsDiff 返回 a 中不在 b 中的版本
+func versionsDiff(a, b []string) []string {
+	var diff []string
+	for _, version := range a {
+		found := false
+		for _, other := range b {
+			if version == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			diff = append(diff, version)
+		}
+	}
+	return diff
+}
+
+// detectPackageManager 返回包管理器、数据来源及用于判断变化的数据库文件
+// 同时存在两者时（如 Debian 上安装了 rpm 工具）以 dpkg 为准
+func detectPackageManager() (manager, source string, files []string) {
+	if _, err := os.Stat(dpkgStatusFile); err == nil {
+		return "dpkg", "dpkg-status", []string{dpkgStatusFile}
+	}
+	for _, dir := range rpmDBDirs {
+		if path, format := rpmdb.Files(dir); path != "" {
+			// sqlite 的 WAL 文件变化同样意味着数据库更新
+			return "rpm", "rpmdb-" + format, []string{path, path + "-wal"}
+		}
+	}
+	return "", "", nil
+}
+
+// fileSignature 由文件的修改时间与大小组成，不存在的文件忽略
+func fileSignature(files []string) string {
+	var parts []string
+	for _, path := range files {
+		if info, err := os.Stat(path); err == nil {
+			parts = append(parts, fmt.Sprintf("%s:%d:%d", path, info.ModTime().UnixNano(), info.Size()))
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// readDpkgStatus 解析 dpkg status 文件，只保留状态为 installed 的软件包
+func readDpkgStatus(path string) ([]models.InstalledPackage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var packages []models.InstalledPackage
+	var pkg models.InstalledPackage
+	installed := false
+	flush := func() {
+		if installed && pkg.Name != "" {
+			packages = append(packages, pkg)
+		}
+		pkg = models.InstalledPackage{}
+		installed = false
+	}
+
+	scanner := bufio.NewScanner(file)
+	// Description 等字段可能很长
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		// 以空白开头的是多行字段的续行
+		if line[0] == ' ' || line[0] == '\t' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "Package":
+			pkg.Name = value
+		case "Version":
+			pkg.Version = value
+		case "Architecture":
+			pkg.Arch = value
+		case "Status":
+			// "<want> <flag> <status>"，如 "install ok installed"、"hold ok installed"
+			fields := strings.Fields(value)
+			installed = len(fields) == 3 && fields[2] == "installed"
+		}
+	}
+	flush()
+	return packages, scanner.Err()
+}
+
+// readRPMDatabase 直接读取 rpm 数据库
+func readRPMDatabase(path, source string) ([]models.InstalledPackage, error) {
+	records, err := rpmdb.Read(path, strings.TrimPrefix(source, "rpmdb-"))
+	if err != nil {
+		return nil, err
+	}
+	packages := make([]models.InstalledPackage, 0, len(records))
+	for _, record := range records {
+		packages = append(packages, models.InstalledPackage{Name: record.Name, Version: record.Version, Arch: record.Arch})
+	}
+	return packages, nil
+}
+
+// queryRPMAsync 在后台执行 rpm -qa，查询完成时返回结果，否则返回 false
+// 同一时间只执行一个查询
+func (p *PackageCollector) queryRPMAsync(dbPath, signature string) (rpmQueryResult, bool) {
+	if p.rpmQuery == nil {
+		query := make(chan rpmQueryResult, 1)
+		p.rpmQuery = query
+		go func() {
+			packages, err := queryRPM(dbPath)
+			query <- rpmQueryResult{packages: packages, signature: signature, err: err}
+		}()
+	}
+
+	select {
+	case result := <-p.rpmQuery:
+		p.rpmQuery = nil
+		return result, true
+	default:
+		return rpmQueryResult{}, false
+	}
+}
+
+// pending 后台查询未完成时的指标：沿用上一次的结果，首次查询时清单为空
+func (p *PackageCollector) pending(manager string) models.PackageMetrics {
+	if p.versions != nil {
+		return p.last
+	}
+	return models.PackageMetrics{
+		Manager:       manager,
+		Source:        "rpm-qa",
+		Error:         "rpm -qa in progress",
+		RecentChanges: []models.PackageChange{},
+		Packages:      []models.InstalledPackage{},
+	}
+}
+
+// queryRPM 通过 rpm -qa 查询，排除 gpg-pubkey 伪包
+func queryRPM(dbPath string) ([]models.InstalledPackage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rpmQueryTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "rpm", "-qa", "--dbpath", dbPath, "--queryformat", rpmQueryFormat).Output()
+	if err != nil {
+		return nil, fmt.Errorf("rpm -qa: %v", err)
+	}
+
+	var packages []models.InstalledPackage
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || fields[0] == "gpg-pubkey" {
+			continue
+		}
+		packages = append(packages, models.InstalledPackage{Name: fields[0], Version: fields[1], Arch: fields[2]})
+	}
+	return packages, nil
+}
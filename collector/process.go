@@ -0,0 +1,116 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procInfo 进程标识
+type procInfo struct {
+	PID  int32
+	Name string
+}
+
+// socketOwnerCache 跨采集周期缓存套接字 inode 与所属进程
+// 遍历所有进程的 fd 代价与进程持有的 fd 总数成正比，只为新出现的 inode 重新遍历
+type socketOwnerCache struct {
+	owners map[uint32]procInfo
+}
+
+// lookup 返回各 inode 的所属进程，找不到的 inode 对应零值
+// 只缓存找到所属进程的 inode，未找到的在下一个周期重新查找
+func (c *socketOwnerCache) lookup(inodes map[uint32]bool) map[uint32]procInfo {
+	result := make(map[uint32]procInfo, len(inodes))
+	missing := make(map[uint32]bool)
+	for inode := range inodes {
+		owner, ok := c.owners[inode]
+		// 所属进程退出后套接字可能由子进程继承，需要重新查找
+		if ok && pidAlive(owner.PID) {
+			result[inode] = owner
+			continue
+		}
+		missing[inode] = true
+	}
+
+	found := findSocketOwners(missing)
+	owners := make(map[uint32]procInfo, len(inodes))
+	for inode, owner := range result {
+		owners[inode] = owner
+	}
+	for inode := range missing {
+		owner := found[inode]
+		result[inode] = owner
+		if owner.PID > 0 {
+			owners[inode] = owner
+		}
+	}
+
+	// 只保留本次仍存在的 inode
+	c.owners = owners
+	return result
+}
+
+// pidAlive 判断进程是否仍在运行，pid 无效时返回 false
+func pidAlive(pid int32) bool {
+	if pid <= 0 {
+		return false
+	}
+	_, err := os.Stat("/proc/" + strconv.Itoa(int(pid)))
+	return err == nil
+}
+
+// findSocketOwners 遍历 /proc/<pid>/fd 查找持有指定套接字 inode 的进程
+// 只在需要时调用（如监听套接字），全部找到后提前结束
+func findSocketOwners(inodes map[uint32]bool) map[uint32]procInfo {
+	owners := make(map[uint32]procInfo, len(inodes))
+	if len(inodes) == 0 {
+		return owners
+	}
+
+	procDirs, err := os.ReadDir("/proc")
+	if err != nil {
+		return owners
+	}
+
+	for _, dir := range procDirs {
+		pid, err := strconv.ParseInt(dir.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join("/proc", dir.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue // 进程已退出或无权限
+		}
+
+		var name string
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 32)
+			if err != nil || !inodes[uint32(inode)] {
+				continue
+			}
+			if _, found := owners[uint32(inode)]; found {
+				continue
+			}
+
+			if name == "" {
+				name, _ = readSysfsString(filepath.Join("/proc", dir.Name(), "comm"))
+			}
+			owners[uint32(inode)] = procInfo{PID: int32(pid), Name: name}
+		}
+
+		if len(owners) == len(inodes) {
+			break
+		}
+	}
+
+	return owners
+}
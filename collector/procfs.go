@@ -0,0 +1,65 @@
+package collector
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readSysfsString 读取 sysfs/procfs 单值文件并去除首尾空白
+func readSysfsString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readSysfsUint 读取 sysfs/procfs 中的无符号整数
+func readSysfsUint(path string) (uint64, error) {
+	value, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
+
+// readSysfsInt 读取 sysfs/procfs 中的有符号整数（如 speed 可能为 -1）
+func readSysfsInt(path string) (int64, error) {
+	value, err := readSysfsString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// counterRate 计算计数器在两次采集间的每秒速率
+// 计数器回绕或重置（如网卡重新加载驱动）时返回 0
+func counterRate(current, previous uint64, seconds float64) float64 {
+	if seconds <= 0 || current < previous {
+		return 0
+	}
+	return float64(current-previous) / seconds
+}
+
+// readKeyValueFile 读取 "key value" 形式的多行文件（如 cpu.stat、memory.events、vmstat）
+// 无法解析为整数的行被忽略
+func readKeyValueFile(path string) (map[string]uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]uint64)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+
+	return values, nil
+}
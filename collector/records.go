@@ -0,0 +1,19 @@
+package collector
+
+// appendBounded 追加记录并只保留最近 limit 条
+func appendBounded[T any](records []T, record T, limit int) []T {
+	records = append(records, record)
+	if len(records) > limit {
+		records = append(records[:0], records[len(records)-limit:]...)
+	}
+	return records
+}
+
+// reversed 返回倒序副本（新的在前）
+func reversed[T any](records []T) []T {
+	result := make([]T, len(records))
+	for i, record := range records {
+		result[len(records)-1-i] = record
+	}
+	return result
+}
@@ -1,65 +1,111 @@
 package collector
 
 import (
-	"bufio"
+	"errors"
+	"host-monitor-agent/config"
 	"host-monitor-agent/models"
+	"host-monitor-agent/tailer"
 	"os"
 	"strings"
+	"time"
 )
 
+// securityWindow 登录失败窗口计数的时间窗口
+const securityWindow = 5 * time.Minute
+
+// authLogFiles 认证日志候选路径，使用第一个存在的文件
+var authLogFiles = []string{
+	"/var/log/auth.log", // Debian/Ubuntu
+	"/var/log/secure",   // RHEL/CentOS/Amazon Linux
+}
+
 // SecurityCollector 安全指标采集器
-type SecurityCollector struct{}
+type SecurityCollector struct {
+	StateDir   string                  // 日志读取位置持久化目录
+	BruteForce config.BruteForceConfig // 暴力破解检测与封禁
+
+	eventBuffer
+	tail          *tailer.Tailer
+	loginFailures uint64
+	window        *tailer.WindowCounter
+	auth          *authAnalyzer
+	bruteForce    *bruteForceDetector
+}
 
 // Collect 采集安全指标
+// 增量读取认证日志，只处理上次采集之后新增的行
 func (s *SecurityCollector) Collect() (interface{}, error) {
-	metrics := models.SecurityMetrics{}
-
-	// 统计登录失败次数
-	loginFailures, err := countLoginFailures()
-	if err == nil {
-		metrics.LoginFailures = loginFailures
+	if s.window == nil {
+		s.window = tailer.NewWindowCounter(securityWindow)
+		s.auth = newAuthAnalyzer(securityWindow)
+		s.bruteForce = newBruteForceDetector(s.BruteForce, s.StateDir)
 	}
 
-	return metrics, nil
-}
-
-// countLoginFailures 统计登录失败次数
-func countLoginFailures() (uint64, error) {
-	// 尝试读取不同的日志文件
-	logFiles := []string{
-		"/var/log/auth.log",      // Debian/Ubuntu
-		"/var/log/secure",         // RHEL/CentOS/Amazon Linux
-		"/var/log/faillog",        // 通用
+	metrics := models.SecurityMetrics{
+		WindowSeconds: int(securityWindow.Seconds()),
 	}
 
-	var count uint64
+	if s.tail == nil {
+		path := findAuthLog()
+		if path == "" {
+			// 所有文件都不存在或无权限
+			s.fill(&metrics)
+			return metrics, nil
+		}
+		s.tail = tailer.New(path, tailer.Options{
+			StateFile: tailer.StateFileFor(s.StateDir, path),
+		})
+	}
+	metrics.LogFile = s.tail.Path()
 
-	for _, logFile := range logFiles {
-		file, err := os.Open(logFile)
-		if err != nil {
-			continue // 文件不存在或无权限，尝试下一个
+	err := s.tail.ReadLines(func(line string) {
+		if isLoginFailure(line) {
+			s.loginFailures++
+			// 按日志时间计入窗口，重启或轮转后补读的旧日志不会挤进当前窗口
+			s.window.AddAt(parseSyslogTime(line, time.Now()), "", 1)
 		}
-		defer file.Close()
+		if event, ok := s.auth.observe(line); ok {
+			s.bruteForce.record(event)
+		}
+	})
 
-		scanner := bufio.NewScanner(file)
-		for scanner.Scan() {
-			line := scanner.Text()
+	var persistErr *tailer.PersistError
+	if err != nil && !errors.As(err, &persistErr) {
+		// 文件被删除或权限变化，下次采集重新查找日志文件
+		// 仅状态目录不可写时保留 Tailer，否则重建后会从末尾开始而丢失两次采集之间的日志
+		s.tail.Close()
+		s.tail = nil
+	}
 
-			// 匹配常见的登录失败关键字
-			if strings.Contains(line, "Failed password") ||
-				strings.Contains(line, "authentication failure") ||
-				strings.Contains(line, "Invalid user") ||
-				strings.Contains(line, "Failed login") {
-				count++
-			}
-		}
+	metrics.LoginFailures = s.loginFailures
+	metrics.LoginFailuresWindow = s.window.Count()
+	s.fill(&metrics)
+
+	return metrics, nil
+}
+
+// fill 写入认证分析结果，并执行暴力破解判定
+func (s *SecurityCollector) fill(metrics *models.SecurityMetrics) {
+	s.auth.fill(metrics)
+	s.bruteForce.evaluate(&s.eventBuffer)
+	s.bruteForce.fill(&metrics.BruteForce)
+}
 
-		// 如果成功读取了一个文件，就返回结果
-		if scanner.Err() == nil {
-			return count, nil
+// findAuthLog 返回第一个可读的认证日志路径
+func findAuthLog() string {
+	for _, path := range authLogFiles {
+		if file, err := os.Open(path); err == nil {
+			file.Close()
+			return path
 		}
 	}
+	return ""
+}
 
-	// 如果所有文件都无法读取，返回0
-	return 0, nil
-}
\ No newline at end of file
+// isLoginFailure 匹配常见的登录失败关键字
+func isLoginFailure(line string) bool {
+	return strings.Contains(line, "Failed password") ||
+		strings.Contains(line, "authentication failure") ||
+		strings.Contains(line, "Invalid user") ||
+		strings.Contains(line, "Failed login")
+}
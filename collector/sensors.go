@@ -0,0 +1,179 @@
+package collector
+
+import (
+	"host-monitor-agent/models"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SensorCollector 硬件传感器采集器
+type SensorCollector struct{}
+
+// Collect 采集 hwmon、thermal zone 温度与 CPU 温控降频计数
+func (s *SensorCollector) Collect() (interface{}, error) {
+	metrics := models.SensorMetrics{}
+
+	collectHwmon(&metrics)
+	collectThermalZones(&metrics)
+	collectThermalThrottle(&metrics)
+
+	return metrics, nil
+}
+
+// collectHwmon 读取 /sys/class/hwmon/hwmon*/ 下的 temp/fan/in 传感器
+func collectHwmon(metrics *models.SensorMetrics) {
+	chips, _ := filepath.Glob("/sys/class/hwmon/hwmon*")
+	for _, chipDir := range chips {
+		chip, err := readSysfsString(filepath.Join(chipDir, "name"))
+		if err != nil {
+			chip = filepath.Base(chipDir)
+		}
+
+		// 旧内核的传感器文件位于 device/ 子目录
+		dirs := []string{chipDir, filepath.Join(chipDir, "device")}
+		for _, dir := range dirs {
+			inputs, _ := filepath.Glob(filepath.Join(dir, "*_input"))
+			sort.Strings(inputs)
+			for _, input := range inputs {
+				prefix := strings.TrimSuffix(filepath.Base(input), "_input")
+				label := hwmonLabel(dir, prefix)
+
+				raw, err := readSysfsInt(input)
+				if err != nil {
+					continue // 传感器不可用时读取返回 EIO/ENODATA
+				}
+
+				switch {
+				case strings.HasPrefix(prefix, "temp"):
+					sensor := models.TemperatureSensor{
+						Chip:    chip,
+						Sensor:  label,
+						Celsius: milliToUnit(raw),
+					}
+					if v, err := readSysfsInt(filepath.Join(dir, prefix+"_max")); err == nil {
+						sensor.MaxCelsius = milliToUnit(v)
+					}
+					if v, err := readSysfsInt(filepath.Join(dir, prefix+"_crit")); err == nil {
+						sensor.CritCelsius = milliToUnit(v)
+					}
+					metrics.Temperatures = append(metrics.Temperatures, sensor)
+				case strings.HasPrefix(prefix, "fan"):
+					if raw < 0 {
+						continue
+					}
+					metrics.Fans = append(metrics.Fans, models.FanSensor{
+						Chip:   chip,
+						Sensor: label,
+						RPM:    uint64(raw),
+					})
+				case strings.HasPrefix(prefix, "in") && strings.TrimLeft(prefix[2:], "0123456789") == "":
+					// 电压单位为毫伏
+					metrics.Voltages = append(metrics.Voltages, models.VoltageSensor{
+						Chip:   chip,
+						Sensor: label,
+						Volts:  float64(raw) / 1000,
+					})
+				}
+			}
+		}
+	}
+}
+
+// collectThermalZones 读取 /sys/class/thermal/thermal_zone* 温度与 critical 触发点
+func collectThermalZones(metrics *models.SensorMetrics) {
+	zones, _ := filepath.Glob("/sys/class/thermal/thermal_zone*")
+	for _, zoneDir := range zones {
+		raw, err := readSysfsInt(filepath.Join(zoneDir, "temp"))
+		if err != nil {
+			continue
+		}
+
+		zoneType, err := readSysfsString(filepath.Join(zoneDir, "type"))
+		if err != nil {
+			zoneType = "thermal"
+		}
+
+		sensor := models.TemperatureSensor{
+			Chip:    zoneType,
+			Sensor:  filepath.Base(zoneDir),
+			Celsius: milliToUnit(raw),
+		}
+
+		trips, _ := filepath.Glob(filepath.Join(zoneDir, "trip_point_*_type"))
+		for _, trip := range trips {
+			tripType, err := readSysfsString(trip)
+			if err != nil {
+				continue
+			}
+			temp, err := readSysfsInt(strings.TrimSuffix(trip, "_type") + "_temp")
+			if err != nil {
+				continue
+			}
+			switch tripType {
+			case "critical":
+				sensor.CritCelsius = milliToUnit(temp)
+			case "hot":
+				sensor.MaxCelsius = milliToUnit(temp)
+			}
+		}
+
+		metrics.Temperatures = append(metrics.Temperatures, sensor)
+	}
+}
+
+// collectThermalThrottle 读取 /sys/devices/system/cpu/cpu*/thermal_throttle 降频计数
+// package_throttle_count 在同一物理封装的所有CPU上相同，汇总时按封装去重
+func collectThermalThrottle(metrics *models.SensorMetrics) {
+	cpuDirs, _ := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*")
+	packages := make(map[string]uint64)
+
+	for _, cpuDir := range cpuDirs {
+		cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(cpuDir), "cpu"))
+		if err != nil {
+			continue
+		}
+
+		throttleDir := filepath.Join(cpuDir, "thermal_throttle")
+		if _, err := os.Stat(throttleDir); err != nil {
+			continue
+		}
+
+		throttle := models.CPUThrottle{CPU: cpu}
+		throttle.CoreThrottleCount, _ = readSysfsUint(filepath.Join(throttleDir, "core_throttle_count"))
+		throttle.PackageThrottleCount, _ = readSysfsUint(filepath.Join(throttleDir, "package_throttle_count"))
+
+		metrics.CPUThrottle = append(metrics.CPUThrottle, throttle)
+		metrics.CoreThrottleTotal += throttle.CoreThrottleCount
+
+		pkg, err := readSysfsString(filepath.Join(cpuDir, "topology", "physical_package_id"))
+		if err != nil {
+			pkg = strconv.Itoa(cpu)
+		}
+		packages[pkg] = throttle.PackageThrottleCount
+	}
+
+	for _, count := range packages {
+		metrics.PackageThrottleTotal += count
+	}
+
+	sort.Slice(metrics.CPUThrottle, func(i, j int) bool {
+		return metrics.CPUThrottle[i].CPU < metrics.CPUThrottle[j].CPU
+	})
+}
+
+// hwmonLabel 读取传感器标签，没有 _label 文件时使用文件前缀（如 temp1）
+func hwmonLabel(dir, prefix string) string {
+	if label, err := readSysfsString(filepath.Join(dir, prefix+"_label")); err == nil && label != "" {
+		return label
+	}
+	return prefix
+}
+
+// milliToUnit 千分单位转换并保留1位小数
+func milliToUnit(v int64) float64 {
+	return math.Round(float64(v)/100) / 10
+}
@@ -0,0 +1,97 @@
+package collector
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// ntpEpochOffset 1900-01-01 到 1970-01-01 的秒数
+	ntpEpochOffset = 2208988800
+	// sntpPacketLen SNTP 报文长度
+	sntpPacketLen = 48
+	// sntpTimeout 单个服务器查询超时
+	sntpTimeout = 2 * time.Second
+)
+
+// sntpResult 一次 SNTP 查询结果
+type sntpResult struct {
+	Offset  time.Duration // 服务器时间 - 本机时间
+	RTT     time.Duration
+	Stratum uint8
+}
+
+// querySNTP 向 SNTP 服务器发送一次请求（RFC 4330）
+// server 为 host 或 host:port，未指定端口时使用 123
+func querySNTP(server string, timeout time.Duration) (sntpResult, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	conn, err := net.DialTimeout("udp", server, timeout)
+	if err != nil {
+		return sntpResult{}, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// LI=0, VN=4, Mode=3(client)
+	req := make([]byte, sntpPacketLen)
+	req[0] = 0x23
+	t1 := time.Now()
+	putNTPTime(req[40:48], t1)
+
+	if _, err := conn.Write(req); err != nil {
+		return sntpResult{}, err
+	}
+
+	resp := make([]byte, sntpPacketLen)
+	n, err := conn.Read(resp)
+	if err != nil {
+		return sntpResult{}, err
+	}
+	t4 := time.Now()
+
+	if n < sntpPacketLen {
+		return sntpResult{}, fmt.Errorf("short SNTP response: %d bytes", n)
+	}
+	if mode := resp[0] & 0x07; mode != 4 && mode != 5 {
+		return sntpResult{}, fmt.Errorf("unexpected SNTP mode %d", mode)
+	}
+	stratum := resp[1]
+	if stratum == 0 {
+		// Kiss-o'-Death，参考标识为 ASCII 码
+		return sntpResult{}, fmt.Errorf("SNTP kiss-of-death: %s", string(resp[12:16]))
+	}
+	// 回显的发起时间必须与请求一致，防止串包
+	if binary.BigEndian.Uint64(resp[24:32]) != binary.BigEndian.Uint64(req[40:48]) {
+		return sntpResult{}, errors.New("SNTP originate timestamp mismatch")
+	}
+
+	t2 := getNTPTime(resp[32:40])
+	t3 := getNTPTime(resp[40:48])
+
+	return sntpResult{
+		Offset:  (t2.Sub(t1) + t3.Sub(t4)) / 2,
+		RTT:     t4.Sub(t1) - t3.Sub(t2),
+		Stratum: stratum,
+	}, nil
+}
+
+// putNTPTime 按 NTP 64位时间戳格式写入
+func putNTPTime(b []byte, t time.Time) {
+	seconds := uint64(t.Unix()) + ntpEpochOffset
+	fraction := uint64(t.Nanosecond()) << 32 / 1e9
+	binary.BigEndian.PutUint64(b, seconds<<32|fraction)
+}
+
+// getNTPTime 解析 NTP 64位时间戳
+func getNTPTime(b []byte) time.Time {
+	v := binary.BigEndian.Uint64(b)
+	seconds := int64(v>>32) - ntpEpochOffset
+	nanos := int64((v & 0xffffffff) * 1e9 >> 32)
+	return time.Unix(seconds, nanos)
+}
@@ -0,0 +1,136 @@
+package collector
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startSNTPResponder 在本机 UDP 端口上模拟 SNTP 服务器，reply 根据请求构造响应，返回 nil 时不回复
+func startSNTPResponder(t *testing.T, reply func(req []byte) []byte) string {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Skipf("udp not available: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			if resp := reply(buf[:n]); resp != nil {
+				conn.WriteToUDP(resp, addr)
+			}
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// sntpReply 构造服务器响应：时钟比本机快 offset
+func sntpReply(req []byte, stratum uint8, offset time.Duration) []byte {
+	resp := make([]byte, sntpPacketLen)
+	resp[0] = 0x24 // LI=0, VN=4, Mode=4(server)
+	resp[1] = stratum
+	copy(resp[24:32], req[40:48])
+	now := time.Now().Add(offset)
+	putNTPTime(resp[32:40], now)
+	putNTPTime(resp[40:48], now)
+	return resp
+}
+
+func TestQuerySNTP(t *testing.T) {
+	server := startSNTPResponder(t, func(req []byte) []byte {
+		return sntpReply(req, 2, 1500*time.Millisecond)
+	})
+
+	result, err := querySNTP(server, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Stratum != 2 {
+		t.Errorf("stratum = %d, want 2", result.Stratum)
+	}
+	if diff := result.Offset - 1500*time.Millisecond; diff < -50*time.Millisecond || diff > 50*time.Millisecond {
+		t.Errorf("offset = %v, want about 1.5s", result.Offset)
+	}
+	if result.RTT < 0 || result.RTT > time.Second {
+		t.Errorf("rtt = %v", result.RTT)
+	}
+}
+
+func TestQuerySNTPRejectsBadResponses(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply func(req []byte) []byte
+		want  string
+	}{
+		{
+			name: "kiss-of-death",
+			reply: func(req []byte) []byte {
+				resp := sntpReply(req, 0, 0)
+				copy(resp[12:16], "RATE")
+				return resp
+			},
+			want: "kiss-of-death: RATE",
+		},
+		{
+			name: "originate mismatch",
+			reply: func(req []byte) []byte {
+				resp := sntpReply(req, 2, 0)
+				resp[31] ^= 0xff
+				return resp
+			},
+			want: "originate timestamp mismatch",
+		},
+		{
+			name: "client mode",
+			reply: func(req []byte) []byte {
+				resp := sntpReply(req, 2, 0)
+				resp[0] = 0x23
+				return resp
+			},
+			want: "unexpected SNTP mode 3",
+		},
+		{
+			name: "short packet",
+			reply: func(req []byte) []byte {
+				return sntpReply(req, 2, 0)[:20]
+			},
+			want: "short SNTP response",
+		},
+	}
+	for _, tt := range tests {
+		server := startSNTPResponder(t, tt.reply)
+		_, err := querySNTP(server, time.Second)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestQuerySNTPTimeout(t *testing.T) {
+	// 不回复的服务器
+	server := startSNTPResponder(t, func(req []byte) []byte { return nil })
+
+	start := time.Now()
+	if _, err := querySNTP(server, 200*time.Millisecond); err == nil {
+		t.Fatal("expected timeout")
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond || elapsed > time.Second {
+		t.Errorf("timeout took %v, want about 200ms", elapsed)
+	}
+}
+
+func TestNTPTimeRoundTrip(t *testing.T) {
+	want := time.Date(2026, 10, 19, 8, 30, 15, 123456000, time.UTC)
+	b := make([]byte, 8)
+	putNTPTime(b, want)
+	if got := getNTPTime(b); got.Sub(want).Abs() > time.Microsecond {
+		t.Errorf("round trip = %v, want %v", got, want)
+	}
+}
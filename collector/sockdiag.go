@@ -0,0 +1,181 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// 内核 TCP 状态编号（include/net/tcp_states.h），netlink 与 /proc/net/tcp 共用
+const (
+	tcpEstablished uint8 = 1 + iota
+	tcpSynSent
+	tcpSynRecv
+	tcpFinWait1
+	tcpFinWait2
+	tcpTimeWait
+	tcpClose
+	tcpCloseWait
+	tcpLastAck
+	tcpListen
+	tcpClosing
+	tcpNewSynRecv
+)
+
+// tcpAllStates 匹配所有 TCP 状态的位图
+const tcpAllStates uint32 = 0xffffffff
+
+// errSockDiagUnsupported 当前平台不支持 NETLINK_SOCK_DIAG
+var errSockDiagUnsupported = errors.New("sock_diag netlink is not supported on this platform")
+
+// socketEntry 内核套接字表中的一条记录
+type socketEntry struct {
+	Family     uint8 // syscall.AF_INET / syscall.AF_INET6
+	State      uint8
+	LocalIP    net.IP
+	LocalPort  uint16
+	RemoteIP   net.IP // 未连接时为全零地址
+	RemotePort uint16
+	RxQueue    uint32 // LISTEN 状态下为当前 accept 队列长度
+	TxQueue    uint32 // netlink 下 LISTEN 状态为 accept 队列上限
+	UID        uint32
+	Inode      uint32
+}
+
+// readSockets 读取指定协议的套接字表
+// 每个协议族优先使用 NETLINK_SOCK_DIAG，失败时回退解析 /proc/net/<proto>{,6}
+// 返回数据来源（"netlink"、"procfs"，两个协议族来源不同时为 "netlink+procfs"）
+func readSockets(protocol uint8, states uint32) ([]socketEntry, string, error) {
+	var entries []socketEntry
+	var sources []string
+	for _, family := range []uint8{syscall.AF_INET, syscall.AF_INET6} {
+		result, source, err := readFamilySockets(family, protocol, states)
+		if err != nil {
+			return nil, "", err
+		}
+		entries = append(entries, result...)
+		if source != "" && (len(sources) == 0 || sources[0] != source) {
+			sources = append(sources, source)
+		}
+	}
+	return entries, strings.Join(sources, "+"), nil
+}
+
+// readFamilySockets 读取单个协议族的套接字表
+// IPv6 被禁用（如 ipv6.disable=1）时 netlink 与 tcp6 文件均不可用，返回空结果与空来源
+func readFamilySockets(family, protocol uint8, states uint32) ([]socketEntry, string, error) {
+	entries, netlinkErr := sockDiagDump(family, protocol, states)
+	if netlinkErr == nil {
+		return entries, "netlink", nil
+	}
+
+	path := "/proc/net/tcp"
+	if protocol == syscall.IPPROTO_UDP {
+		path = "/proc/net/udp"
+	}
+	if family == syscall.AF_INET6 {
+		path += "6"
+	}
+
+	result, err := parseProcNetSockets(path, family)
+	if err != nil {
+		if family == syscall.AF_INET6 && errors.Is(err, os.ErrNotExist) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("netlink: %v; procfs: %v", netlinkErr, err)
+	}
+	entries = result[:0]
+	for _, entry := range result {
+		if states&(1<<entry.State) != 0 {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, "procfs", nil
+}
+
+// parseProcNetSockets 解析 /proc/net/tcp 格式的套接字表
+func parseProcNetSockets(path string, family uint8) ([]socketEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []socketEntry
+	scanner := bufio.NewScanner(file)
+	// 跳过表头
+	scanner.Scan()
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		ip, port, err := parseProcNetAddr(fields[1])
+		if err != nil {
+			continue
+		}
+		state, err := strconv.ParseUint(fields[3], 16, 8)
+		if err != nil {
+			continue
+		}
+
+		entry := socketEntry{
+			Family:    family,
+			State:     uint8(state),
+			LocalIP:   ip,
+			LocalPort: port,
+		}
+		if remoteIP, remotePort, err := parseProcNetAddr(fields[2]); err == nil {
+			entry.RemoteIP, entry.RemotePort = remoteIP, remotePort
+		}
+		if queues := strings.SplitN(fields[4], ":", 2); len(queues) == 2 {
+			tx, _ := strconv.ParseUint(queues[0], 16, 32)
+			rx, _ := strconv.ParseUint(queues[1], 16, 32)
+			entry.TxQueue, entry.RxQueue = uint32(tx), uint32(rx)
+		}
+		if uid, err := strconv.ParseUint(fields[7], 10, 32); err == nil {
+			entry.UID = uint32(uid)
+		}
+		if inode, err := strconv.ParseUint(fields[9], 10, 32); err == nil {
+			entry.Inode = uint32(inode)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// parseProcNetAddr 解析 "0100007F:1F90" 形式的地址
+// 地址按 32 位字以主机字节序输出，端口为大端十六进制
+func parseProcNetAddr(s string) (net.IP, uint16, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return nil, 0, fmt.Errorf("invalid address %q", s)
+	}
+
+	raw, err := hex.DecodeString(parts[0])
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid address %q", s)
+	}
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		binary.NativeEndian.PutUint32(ip[i:], binary.BigEndian.Uint32(raw[i:]))
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid port %q", s)
+	}
+
+	return ip, uint16(port), nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package collector
+
+// sockDiagDump 非 Linux 平台不支持 NETLINK_SOCK_DIAG
+func sockDiagDump(family, protocol uint8, states uint32) ([]socketEntry, error) {
+	return nil, errSockDiagUnsupported
+}
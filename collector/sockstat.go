@@ -0,0 +1,92 @@
+package collector
+
+import (
+	"bufio"
+	"host-monitor-agent/models"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SockStatCollector 套接字汇总采集器
+type SockStatCollector struct{}
+
+// Collect 采集 /proc/net/sockstat{,6} 与 tcp_mem 限制
+func (s *SockStatCollector) Collect() (interface{}, error) {
+	stat, err := parseSockStat("/proc/net/sockstat")
+	if err != nil {
+		return models.SockStatMetrics{}, err
+	}
+	// IPv6 被禁用时 sockstat6 不存在
+	stat6, err := parseSockStat("/proc/net/sockstat6")
+	if err != nil {
+		stat6 = map[string]map[string]uint64{}
+	}
+
+	metrics := models.SockStatMetrics{
+		SocketsUsed: stat["sockets"]["used"],
+		TCPInUse:    stat["TCP"]["inuse"],
+		TCPOrphan:   stat["TCP"]["orphan"],
+		TCPTimeWait: stat["TCP"]["tw"],
+		TCPAlloc:    stat["TCP"]["alloc"],
+		TCPMemPages: stat["TCP"]["mem"],
+		UDPInUse:    stat["UDP"]["inuse"],
+		UDPMemPages: stat["UDP"]["mem"],
+		RawInUse:    stat["RAW"]["inuse"],
+		TCP6InUse:   stat6["TCP6"]["inuse"],
+		UDP6InUse:   stat6["UDP6"]["inuse"],
+		Raw6InUse:   stat6["RAW6"]["inuse"],
+	}
+
+	// tcp_mem: 下限 压力阈值 上限（单位：页）
+	if tcpMem, err := readSysfsString("/proc/sys/net/ipv4/tcp_mem"); err == nil {
+		if fields := strings.Fields(tcpMem); len(fields) == 3 {
+			metrics.TCPMemLow, _ = strconv.ParseUint(fields[0], 10, 64)
+			metrics.TCPMemPressure, _ = strconv.ParseUint(fields[1], 10, 64)
+			metrics.TCPMemHigh, _ = strconv.ParseUint(fields[2], 10, 64)
+		}
+	}
+	if metrics.TCPMemHigh > 0 {
+		metrics.TCPMemPercent = math.Round(float64(metrics.TCPMemPages)/float64(metrics.TCPMemHigh)*1000) / 10
+	}
+
+	if maxOrphans, err := readSysfsUint("/proc/sys/net/ipv4/tcp_max_orphans"); err == nil {
+		metrics.OrphanMax = maxOrphans
+		if maxOrphans > 0 {
+			metrics.OrphanPercent = math.Round(float64(metrics.TCPOrphan)/float64(maxOrphans)*1000) / 10
+		}
+	}
+
+	return metrics, nil
+}
+
+// parseSockStat 解析 "TCP: inuse 4 orphan 0 tw 2 alloc 5 mem 0" 形式的行
+// 返回 协议 -> 字段 -> 值
+func parseSockStat(path string) (map[string]map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := make(map[string]map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+
+		proto := strings.TrimSuffix(fields[0], ":")
+		values := make(map[string]uint64)
+		for i := 1; i+1 < len(fields); i += 2 {
+			if v, err := strconv.ParseUint(fields[i+1], 10, 64); err == nil {
+				values[fields[i]] = v
+			}
+		}
+		result[proto] = values
+	}
+
+	return result, scanner.Err()
+}
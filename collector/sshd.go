@@ -0,0 +1,236 @@
+package collector
+
+import (
+	"bufio"
+	"host-monitor-agent/models"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sshdConfigFile sshd 主配置文件，Include 的相对路径相对于其所在目录
+const sshdConfigFile = "/etc/ssh/sshd_config"
+
+// sshdMaxAuthTries MaxAuthTries 的上限（OpenSSH 默认值）
+const sshdMaxAuthTries = 6
+
+// sshd 未配置时的默认值（OpenSSH 7.0 及以后）
+var sshdDefaults = map[string]string{
+	"permitrootlogin":        "prohibit-password",
+	"passwordauthentication": "yes",
+	"permitemptypasswords":   "no",
+	"maxauthtries":           "6",
+	"x11forwarding":          "no",
+	"ciphers":                "chacha20-poly1305@openssh.com,aes128-ctr,aes192-ctr,aes256-ctr,aes128-gcm@openssh.com,aes256-gcm@openssh.com",
+	"macs":                   "umac-64-etm@openssh.com,umac-128-etm@openssh.com,hmac-sha2-256-etm@openssh.com,hmac-sha2-512-etm@openssh.com,hmac-sha1-etm@openssh.com,umac-64@openssh.com,umac-128@openssh.com,hmac-sha2-256,hmac-sha2-512,hmac-sha1",
+	"kexalgorithms":          "curve25519-sha256,curve25519-sha256@libssh.org,ecdh-sha2-nistp256,ecdh-sha2-nistp384,ecdh-sha2-nistp521,diffie-hellman-group-exchange-sha256,diffie-hellman-group16-sha512,diffie-hellman-group18-sha512,diffie-hellman-group14-sha256",
+}
+
+// 弱算法（前缀匹配）
+var (
+	weakSSHCiphers = []string{"3des-cbc", "aes128-cbc", "aes192-cbc", "aes256-cbc", "rijndael-cbc", "blowfish-cbc", "cast128-cbc", "arcfour"}
+	weakSSHMACs    = []string{"hmac-md5", "hmac-sha1-96", "hmac-ripemd160", "umac-32"}
+	weakSSHKex     = []string{"diffie-hellman-group1-sha1", "diffie-hellman-group14-sha1", "diffie-hellman-group-exchange-sha1"}
+)
+
+// sshdConfig 解析得到的全局配置，关键字小写
+// 与 sshd 一样每个关键字以第一次出现的值为准，Match 块内的配置不计入
+type sshdConfig struct {
+	values  map[string]string
+	files   []string
+	inMatch bool
+}
+
+// SSHDConfigCollector sshd 配置加固检查采集器
+type SSHDConfigCollector struct{}
+
+// Collect 解析 sshd 配置并逐项检查
+func (s *SSHDConfigCollector) Collect() (interface{}, error) {
+	metrics := models.SSHDConfigMetrics{
+		Files:  []string{},
+		Checks: []models.ComplianceCheck{},
+	}
+
+	config := &sshdConfig{values: make(map[string]string)}
+	if err := config.parseFile(sshdConfigFile, 0); err != nil {
+		// 未安装 sshd 或无权限
+		return metrics, nil
+	}
+	metrics.Available = true
+	metrics.Files = config.files
+	metrics.Checks = config.checks()
+
+	for _, check := range metrics.Checks {
+		if check.Passed {
+			metrics.Passed++
+		} else {
+			metrics.Failed++
+		}
+	}
+
+	return metrics, nil
+}
+
+// parseFile 解析配置文件，Include 的文件按 glob 排序后就地展开
+func (c *sshdConfig) parseFile(path string, depth int) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	c.files = append(c.files, path)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// "Keyword value" 或 "Keyword=value"
+		keyword, args := line, ""
+		if i := strings.IndexAny(line, " \t="); i >= 0 {
+			keyword = line[:i]
+			args = strings.TrimLeft(line[i:], " \t=")
+		}
+		keyword = strings.ToLower(keyword)
+
+		switch keyword {
+		case "include":
+			// OpenSSH 限制 Include 嵌套深度为 16
+			if depth >= 16 {
+				continue
+			}
+			for _, pattern := range strings.Fields(args) {
+				pattern = strings.Trim(pattern, `"`)
+				if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(filepath.Dir(sshdConfigFile), pattern)
+				}
+				matches, _ := filepath.Glob(pattern)
+				for _, match := range matches {
+					// 被包含文件末尾的 Match 块不延续到包含它的文件
+					inMatch := c.inMatch
+					c.parseFile(match, depth+1)
+					c.inMatch = inMatch
+				}
+			}
+		case "match":
+			// "Match all" 结束条件块
+			c.inMatch = strings.ToLower(strings.TrimSpace(args)) != "all"
+		default:
+			if c.inMatch {
+				continue
+			}
+			if _, ok := c.values[keyword]; !ok {
+				c.values[keyword] = strings.Trim(args, `"`)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// get 返回生效的值，未配置时返回默认值
+func (c *sshdConfig) get(keyword string) string {
+	if value, ok := c.values[keyword]; ok {
+		return value
+	}
+	return sshdDefaults[keyword]
+}
+
+// algorithms 计算算法列表，支持 +（追加）、-（移除）、^（前置）相对默认值的写法
+func (c *sshdConfig) algorithms(keyword string) []string {
+	value := c.get(keyword)
+	defaults := strings.Split(sshdDefaults[keyword], ",")
+	if value == "" {
+		return defaults
+	}
+
+	items := strings.Split(value[1:], ",")
+	switch value[0] {
+	case '+':
+		return append(defaults, items...)
+	case '^':
+		return append(items, defaults...)
+	case '-':
+		var result []string
+		for _, algorithm := range defaults {
+			removed := false
+			for _, pattern := range items {
+				if matched, _ := filepath.Match(pattern, algorithm); matched {
+					removed = true
+				}
+			}
+			if !removed {
+				result = append(result, algorithm)
+			}
+		}
+		return result
+	}
+	return strings.Split(value, ",")
+}
+
+// checks 逐项检查
+func (c *sshdConfig) checks() []models.ComplianceCheck {
+	checks := []models.ComplianceCheck{}
+	add := func(id, title, severity string, passed bool, value, expected string) {
+		checks = append(checks, models.ComplianceCheck{
+			ID:       id,
+			Title:    title,
+			Severity: severity,
+			Passed:   passed,
+			Value:    value,
+			Expected: expected,
+		})
+	}
+
+	rootLogin := strings.ToLower(c.get("permitrootlogin"))
+	add("sshd_permit_root_login", "Root login is restricted", "high",
+		rootLogin != "yes", rootLogin, "no, prohibit-password or forced-commands-only")
+
+	password := strings.ToLower(c.get("passwordauthentication"))
+	add("sshd_password_authentication", "Password authentication is disabled", "medium",
+		password == "no", password, "no")
+
+	empty := strings.ToLower(c.get("permitemptypasswords"))
+	add("sshd_permit_empty_passwords", "Empty passwords are not permitted", "high",
+		empty == "no", empty, "no")
+
+	tries := c.get("maxauthtries")
+	n, err := strconv.Atoi(tries)
+	add("sshd_max_auth_tries", "MaxAuthTries is limited", "medium",
+		err == nil && n > 0 && n <= sshdMaxAuthTries, tries, "1-"+strconv.Itoa(sshdMaxAuthTries))
+
+	x11 := strings.ToLower(c.get("x11forwarding"))
+	add("sshd_x11_forwarding", "X11 forwarding is disabled", "low",
+		x11 == "no", x11, "no")
+
+	weak := weakAlgorithms(c.algorithms("ciphers"), weakSSHCiphers)
+	add("sshd_weak_ciphers", "No weak ciphers are enabled", "high",
+		len(weak) == 0, strings.Join(weak, ","), "no CBC, 3DES, Blowfish, CAST or RC4 ciphers")
+
+	weak = weakAlgorithms(c.algorithms("macs"), weakSSHMACs)
+	add("sshd_weak_macs", "No weak MACs are enabled", "medium",
+		len(weak) == 0, strings.Join(weak, ","), "no MD5, RIPEMD-160, 96-bit or 32-bit MACs")
+
+	weak = weakAlgorithms(c.algorithms("kexalgorithms"), weakSSHKex)
+	add("sshd_weak_kex", "No weak key exchange algorithms are enabled", "medium",
+		len(weak) == 0, strings.Join(weak, ","), "no SHA-1 Diffie-Hellman key exchange")
+
+	return checks
+}
+
+// weakAlgorithms 返回列表中匹配弱算法前缀的项
+func weakAlgorithms(algorithms, weak []string) []string {
+	result := []string{}
+	for _, algorithm := range algorithms {
+		algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+		for _, prefix := range weak {
+			if strings.HasPrefix(algorithm, prefix) {
+				result = append(result, algorithm)
+				break
+			}
+		}
+	}
+	return result
+}
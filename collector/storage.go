@@ -0,0 +1,387 @@
+package collector
+
+import (
+	"bufio"
+	"host-monitor-agent/models"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 存储栈状态文件
+const (
+	mdstatFile    = "/proc/mdstat"
+	dmSysfsGlob   = "/sys/block/dm-*/dm/name"
+	zfsKstatDir   = "/proc/spl/kstat/zfs"
+	thinMetaBlock = 4096 // thin-pool 元数据块大小固定为 4KB
+)
+
+var (
+	// md0 : active (auto-read-only) raid1 sdb1[1] sda1[0](F)
+	mdArrayPattern = regexp.MustCompile(`^(md\S*) : (.*)$`)
+	// sda1[0](W)(F)
+	mdMemberPattern = regexp.MustCompile(`^(\S+?)\[(\d+)\]((?:\(\w\))*)$`)
+	// 1046528 blocks super 1.2 [2/1] [U_]
+	mdDevicesPattern = regexp.MustCompile(`\[(\d+)/(\d+)\] \[([U_]+)\]`)
+	// [=>...................]  recovery =  8.5% (89216/1046528) finish=0.5min speed=29738K/sec
+	mdSyncPattern = regexp.MustCompile(`(resync|recovery|reshape|check|repair)\s*=\s*([\d.]+)%.*?finish=([\d.]+)min(?:.*?speed=(\d+)K/sec)?`)
+	// resync=DELAYED / resync=PENDING
+	mdSyncPendingPattern = regexp.MustCompile(`(resync|recovery|reshape|check|repair)\s*=\s*(DELAYED|PENDING)`)
+)
+
+// StorageCollector 软 RAID、LVM thin-pool 与 ZFS 健康状态采集器
+type StorageCollector struct {
+	lastARCHits   uint64
+	lastARCMisses uint64
+	lastARCRatio  float64
+}
+
+// Collect 采集 md 阵列、thin-pool 与 ZFS 状态，未使用的部分为空
+func (s *StorageCollector) Collect() (interface{}, error) {
+	metrics := models.StorageMetrics{
+		MDArrays:  []models.MDArray{},
+		ThinPools: []models.ThinPool{},
+		ZFS:       models.ZFSMetrics{Pools: []models.ZFSPool{}},
+	}
+
+	fillMDArrays(&metrics, mdstatFile)
+
+	pools, err := readThinPools()
+	metrics.ThinPools = pools
+	if err != nil {
+		metrics.ThinPoolError = err.Error()
+	}
+
+	metrics.ZFS = s.readZFS(zfsKstatDir)
+
+	return metrics, nil
+}
+
+// fillMDArrays 读取 md 阵列并统计降级阵列数
+// 未加载 md 模块时 /proc/mdstat 不存在，不视为错误；其他读取失败写入 MDStatError，
+// 以免与“没有阵列、没有降级”混淆
+func fillMDArrays(metrics *models.StorageMetrics, path string) {
+	arrays, err := readMDStat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			metrics.MDStatError = err.Error()
+		}
+		return
+	}
+	metrics.MDArrays = arrays
+	for _, array := range arrays {
+		if array.Degraded {
+			metrics.DegradedArrays++
+		}
+	}
+}
+
+// readMDStat 解析 /proc/mdstat
+//
+//	md1 : active raid1 sdb2[2](F) sda2[0]
+//	      1046528 blocks super 1.2 [2/1] [U_]
+//	      [=>...................]  recovery =  8.5% (89216/1046528) finish=0.5min speed=29738K/sec
+func readMDStat(path string) ([]models.MDArray, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	arrays := []models.MDArray{}
+	var array *models.MDArray
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := mdArrayPattern.FindStringSubmatch(line); m != nil {
+			arrays = append(arrays, parseMDArrayLine(m[1], strings.Fields(m[2])))
+			array = &arrays[len(arrays)-1]
+			continue
+		}
+		if array == nil || strings.TrimSpace(line) == "" {
+			array = nil
+			continue
+		}
+
+		if m := mdDevicesPattern.FindStringSubmatch(line); m != nil {
+			array.Devices, _ = strconv.Atoi(m[1])
+			array.ActiveDevices, _ = strconv.Atoi(m[2])
+			array.Status = m[3]
+			array.Degraded = array.ActiveDevices < array.Devices
+		}
+		if fields := strings.Fields(line); len(fields) > 1 && fields[1] == "blocks" {
+			if blocks, err := strconv.ParseUint(fields[0], 10, 64); err == nil {
+				array.SizeBytes = blocks * 1024
+			}
+		}
+		if m := mdSyncPattern.FindStringSubmatch(line); m != nil {
+			array.SyncAction = m[1]
+			array.SyncProgress, _ = strconv.ParseFloat(m[2], 64)
+			array.SyncFinishMinutes, _ = strconv.ParseFloat(m[3], 64)
+			array.SyncSpeedKBps, _ = strconv.ParseUint(m[4], 10, 64)
+		} else if m := mdSyncPendingPattern.FindStringSubmatch(line); m != nil {
+			array.SyncAction = m[1]
+			array.SyncPending = true
+		}
+	}
+
+	return arrays, scanner.Err()
+}
+
+// parseMDArrayLine 解析阵列首行 "<状态> [(read-only)] <级别> <成员>..."
+// inactive 阵列没有级别
+func parseMDArrayLine(name string, fields []string) models.MDArray {
+	array := models.MDArray{
+		Name:    name,
+		Members: []models.MDMember{},
+		Failed:  []string{},
+	}
+	if len(fields) == 0 {
+		return array
+	}
+	array.State = fields[0]
+	fields = fields[1:]
+
+	if len(fields) > 0 && strings.HasPrefix(fields[0], "(") {
+		array.ReadOnly = strings.Contains(fields[0], "read-only")
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && !strings.Contains(fields[0], "[") {
+		array.Level = fields[0]
+		fields = fields[1:]
+	}
+
+	for _, field := range fields {
+		m := mdMemberPattern.FindStringSubmatch(field)
+		if m == nil {
+			continue
+		}
+		member := models.MDMember{Device: m[1], State: "in_sync"}
+		member.Slot, _ = strconv.Atoi(m[2])
+		// 标志：F 故障、S 备用、W write-mostly、J 日志盘、R 替换盘
+		for _, flag := range strings.Split(strings.Trim(m[3], "()"), ")(") {
+			switch flag {
+			case "F":
+				member.State = "faulty"
+			case "S":
+				if member.State != "faulty" {
+					member.State = "spare"
+				}
+			case "J":
+				if member.State == "in_sync" {
+					member.State = "journal"
+				}
+			case "R":
+				if member.State == "in_sync" {
+					member.State = "replacement"
+				}
+			case "W":
+				member.WriteMostly = true
+			}
+		}
+		switch member.State {
+		case "faulty":
+			array.Failed = append(array.Failed, member.Device)
+		case "spare":
+			array.Spares++
+		}
+		array.Members = append(array.Members, member)
+	}
+	sort.Slice(array.Members, func(i, j int) bool { return array.Members[i].Slot < array.Members[j].Slot })
+
+	// 有故障成员即视为降级（mdadm 尚未更新计数时也能发现）
+	if len(array.Failed) > 0 {
+		array.Degraded = true
+	}
+	return array
+}
+
+// readThinPools 遍历 device-mapper 设备，读取 thin-pool 目标的状态
+func readThinPools() ([]models.ThinPool, error) {
+	pools := []models.ThinPool{}
+	names, _ := filepath.Glob(dmSysfsGlob)
+	var lastErr error
+	for _, path := range names {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		name := strings.TrimSpace(string(data))
+		targets, err := dmStatus(name)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, target := range targets {
+			if target.Type != "thin-pool" {
+				continue
+			}
+			pools = append(pools, parseThinPoolStatus(name, target))
+		}
+	}
+	sort.Slice(pools, func(i, j int) bool { return pools[i].Name < pools[j].Name })
+	return pools, lastErr
+}
+
+// parseThinPoolStatus 解析 thin-pool 状态
+//
+//	<transaction id> <used meta>/<total meta> <used data>/<total data> <held root> ro|rw|out_of_data_space
+//	[no_]discard_passdown error|queue_if_no_space needs_check|- [metadata_low_watermark]
+//
+// 出错时状态为 "Fail" 或 "Error"
+func parseThinPoolStatus(name string, target dmTarget) models.ThinPool {
+	pool := models.ThinPool{Name: name}
+	// LVM 的设备名为 <vg>-<lv>[-tpool]，名称中的 - 转义为 --
+	pool.VG, pool.LV = splitDMName(strings.TrimSuffix(name, "-tpool"))
+
+	fields := strings.Fields(target.Status)
+	if len(fields) < 5 {
+		pool.Mode = "fail"
+		if len(fields) > 0 {
+			pool.Mode = strings.ToLower(fields[0])
+		}
+		return pool
+	}
+
+	metaUsed, metaTotal := parseFraction(fields[1])
+	dataUsed, dataTotal := parseFraction(fields[2])
+	pool.MetadataTotalBytes = metaTotal * thinMetaBlock
+	pool.MetadataUsedBytes = metaUsed * thinMetaBlock
+	pool.MetadataPercent = percent(metaUsed, metaTotal)
+	// 数据块大小在映射表中，数据区总大小即目标长度
+	pool.DataTotalBytes = target.Length * 512
+	if dataTotal > 0 {
+		pool.DataUsedBytes = pool.DataTotalBytes / dataTotal * dataUsed
+	}
+	pool.DataPercent = percent(dataUsed, dataTotal)
+	pool.Mode = fields[4]
+	if len(fields) > 7 {
+		pool.NeedsCheck = fields[7] == "needs_check"
+	}
+	return pool
+}
+
+// splitDMName 拆分 LVM 设备名 <vg>-<lv>，单个 - 为分隔符，-- 为名称中的 -
+func splitDMName(name string) (vg, lv string) {
+	for i := 0; i < len(name); i++ {
+		if name[i] != '-' {
+			continue
+		}
+		if i+1 < len(name) && name[i+1] == '-' {
+			i++
+			continue
+		}
+		return strings.ReplaceAll(name[:i], "--", "-"), strings.ReplaceAll(name[i+1:], "--", "-")
+	}
+	return "", name
+}
+
+// parseFraction 解析 "used/total"
+func parseFraction(value string) (used, total uint64) {
+	a, b, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0
+	}
+	used, _ = strconv.ParseUint(a, 10, 64)
+	total, _ = strconv.ParseUint(b, 10, 64)
+	return used, total
+}
+
+// percent 百分比，保留1位小数
+func percent(used, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(used)/float64(total)*1000) / 10
+}
+
+// readZFS 读取 ZFS 存储池状态与 ARC 统计
+// 存储池为 kstat 目录下的子目录，state 文件内容如 ONLINE、DEGRADED、FAULTED
+func (s *StorageCollector) readZFS(dir string) models.ZFSMetrics {
+	metrics := models.ZFSMetrics{Pools: []models.ZFSPool{}}
+
+	arc, err := readKstat(filepath.Join(dir, "arcstats"))
+	if err != nil {
+		// 未加载 zfs 模块
+		return metrics
+	}
+	metrics.Available = true
+
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name(), "state"))
+		if err != nil {
+			continue
+		}
+		state := strings.TrimSpace(string(data))
+		pool := models.ZFSPool{Name: entry.Name(), State: state, Healthy: state == "ONLINE"}
+		if !pool.Healthy {
+			metrics.UnhealthyPools++
+		}
+		metrics.Pools = append(metrics.Pools, pool)
+	}
+
+	metrics.ARC = models.ZFSARC{
+		SizeBytes:   arc["size"],
+		TargetBytes: arc["c"],
+		MinBytes:    arc["c_min"],
+		MaxBytes:    arc["c_max"],
+		Hits:        arc["hits"],
+		Misses:      arc["misses"],
+		L2SizeBytes: arc["l2_size"],
+		L2Hits:      arc["l2_hits"],
+		L2Misses:    arc["l2_misses"],
+	}
+	// 命中率优先按两次采集之间的增量计算，首次采集使用累计值；
+	// 期间没有任何访问时沿用上一次的命中率，而不是报告 0%
+	hits, misses := arc["hits"], arc["misses"]
+	ratio := percent(hits, hits+misses)
+	if s.lastARCHits > 0 && hits >= s.lastARCHits && misses >= s.lastARCMisses {
+		deltaHits, deltaMisses := hits-s.lastARCHits, misses-s.lastARCMisses
+		if deltaHits+deltaMisses > 0 {
+			ratio = percent(deltaHits, deltaHits+deltaMisses)
+		} else {
+			ratio = s.lastARCRatio
+		}
+	}
+	metrics.ARC.HitRatio = ratio
+	s.lastARCHits, s.lastARCMisses, s.lastARCRatio = hits, misses, ratio
+
+	return metrics
+}
+
+// readKstat 解析 SPL kstat 命名值文件
+//
+//	13 1 0x01 123 33456 1234 5678
+//	name                            type data
+//	hits                            4    123
+func readKstat(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for line := 0; scanner.Scan(); line++ {
+		if line < 2 {
+			continue
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		if v, err := strconv.ParseUint(fields[2], 10, 64); err == nil {
+			values[fields[0]] = v
+		}
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,224 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"host-monitor-agent/models"
+)
+
+func TestReadMDStat(t *testing.T) {
+	arrays, err := readMDStat(filepath.Join("testdata", "mdstat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(arrays) != 4 {
+		t.Fatalf("got %d arrays, want 4", len(arrays))
+	}
+	byName := make(map[string]models.MDArray)
+	for _, array := range arrays {
+		byName[array.Name] = array
+	}
+
+	// 重建中的 raid5：缺一块盘，recovery 进度
+	md2 := byName["md2"]
+	if md2.Level != "raid5" || md2.Devices != 3 || md2.ActiveDevices != 2 || md2.Status != "UU_" || !md2.Degraded {
+		t.Errorf("md2 = %+v", md2)
+	}
+	if md2.SyncAction != "recovery" || md2.SyncProgress != 8.5 || md2.SyncFinishMinutes != 0.5 || md2.SyncSpeedKBps != 29738 {
+		t.Errorf("md2 sync = %s %v %v %v", md2.SyncAction, md2.SyncProgress, md2.SyncFinishMinutes, md2.SyncSpeedKBps)
+	}
+	if md2.SizeBytes != 2093056*1024 {
+		t.Errorf("md2 size = %d", md2.SizeBytes)
+	}
+
+	// 降级的 raid1：故障成员与备用成员
+	md1 := byName["md1"]
+	if !md1.Degraded || md1.Status != "U_" || md1.Spares != 1 || !reflect.DeepEqual(md1.Failed, []string{"sdb2"}) {
+		t.Errorf("md1 = %+v", md1)
+	}
+	wantMembers := []models.MDMember{
+		{Device: "sda2", Slot: 0, State: "in_sync"},
+		{Device: "sdb2", Slot: 2, State: "faulty"},
+		{Device: "sde2", Slot: 3, State: "spare"},
+	}
+	if !reflect.DeepEqual(md1.Members, wantMembers) {
+		t.Errorf("md1 members = %+v", md1.Members)
+	}
+
+	// 只读阵列，同步被推迟
+	md3 := byName["md3"]
+	if !md3.ReadOnly || md3.Degraded || md3.SyncAction != "resync" || !md3.SyncPending {
+		t.Errorf("md3 = %+v", md3)
+	}
+	if len(md3.Members) != 2 || md3.Members[1].Device != "sdf1" || !md3.Members[1].WriteMostly {
+		t.Errorf("md3 members = %+v", md3.Members)
+	}
+
+	// 未组装的阵列没有级别与成员计数
+	md127 := byName["md127"]
+	if md127.State != "inactive" || md127.Level != "" || md127.Degraded || md127.Spares != 1 {
+		t.Errorf("md127 = %+v", md127)
+	}
+}
+
+func TestFillMDArrays(t *testing.T) {
+	var metrics models.StorageMetrics
+	fillMDArrays(&metrics, filepath.Join("testdata", "mdstat"))
+	if len(metrics.MDArrays) != 4 || metrics.DegradedArrays != 2 || metrics.MDStatError != "" {
+		t.Errorf("mdstat fixture: %d arrays, %d degraded, error %q", len(metrics.MDArrays), metrics.DegradedArrays, metrics.MDStatError)
+	}
+
+	// 未加载 md 模块
+	metrics = models.StorageMetrics{}
+	fillMDArrays(&metrics, filepath.Join(t.TempDir(), "mdstat"))
+	if metrics.MDStatError != "" {
+		t.Errorf("missing mdstat reported as error: %q", metrics.MDStatError)
+	}
+
+	// 读取失败不能表现为“没有阵列”
+	metrics = models.StorageMetrics{}
+	fillMDArrays(&metrics, t.TempDir())
+	if metrics.MDStatError == "" {
+		t.Error("read failure not reported")
+	}
+}
+
+func TestParseThinPoolStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		target dmTarget
+		want   models.ThinPool
+	}{
+		{
+			name: "vg0-pool0-tpool",
+			target: dmTarget{
+				Length: 209715200,
+				Type:   "thin-pool",
+				Status: "1 2048/16384 40960/102400 - rw no_discard_passdown queue_if_no_space - 1024",
+			},
+			want: models.ThinPool{
+				Name:               "vg0-pool0-tpool",
+				VG:                 "vg0",
+				LV:                 "pool0",
+				Mode:               "rw",
+				DataTotalBytes:     209715200 * 512,
+				DataUsedBytes:      209715200 * 512 / 102400 * 40960,
+				DataPercent:        40,
+				MetadataTotalBytes: 16384 * 4096,
+				MetadataUsedBytes:  2048 * 4096,
+				MetadataPercent:    12.5,
+			},
+		},
+		{
+			name: "data--vg-thin--pool-tpool",
+			target: dmTarget{
+				Length: 2048,
+				Type:   "thin-pool",
+				Status: "7 100/100 16/16 - out_of_data_space discard_passdown error_if_no_space needs_check 1024",
+			},
+			want: models.ThinPool{
+				Name:               "data--vg-thin--pool-tpool",
+				VG:                 "data-vg",
+				LV:                 "thin-pool",
+				Mode:               "out_of_data_space",
+				NeedsCheck:         true,
+				DataTotalBytes:     2048 * 512,
+				DataUsedBytes:      2048 * 512,
+				DataPercent:        100,
+				MetadataTotalBytes: 100 * 4096,
+				MetadataUsedBytes:  100 * 4096,
+				MetadataPercent:    100,
+			},
+		},
+		{
+			name:   "vg0-broken-tpool",
+			target: dmTarget{Length: 2048, Type: "thin-pool", Status: "Fail"},
+			want:   models.ThinPool{Name: "vg0-broken-tpool", VG: "vg0", LV: "broken", Mode: "fail"},
+		},
+	}
+	for _, tt := range tests {
+		if got := parseThinPoolStatus(tt.name, tt.target); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseThinPoolStatus(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSplitDMName(t *testing.T) {
+	tests := []struct {
+		name, vg, lv string
+	}{
+		{"vg0-root", "vg0", "root"},
+		{"data--vg-thin--pool", "data-vg", "thin-pool"},
+		{"vg0-lv--with--dashes", "vg0", "lv-with-dashes"},
+		{"a--b--c-d", "a-b-c", "d"},
+		{"nodash", "", "nodash"},
+	}
+	for _, tt := range tests {
+		vg, lv := splitDMName(tt.name)
+		if vg != tt.vg || lv != tt.lv {
+			t.Errorf("splitDMName(%q) = %q, %q, want %q, %q", tt.name, vg, lv, tt.vg, tt.lv)
+		}
+	}
+}
+
+func TestReadKstat(t *testing.T) {
+	values, err := readKstat(filepath.Join("testdata", "zfs", "arcstats"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]uint64{
+		"hits":      900,
+		"misses":    100,
+		"c":         4294967296,
+		"c_min":     520093696,
+		"c_max":     8321499136,
+		"size":      3221225472,
+		"l2_hits":   0,
+		"l2_misses": 0,
+		"l2_size":   0,
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("readKstat = %v, want %v", values, want)
+	}
+}
+
+func TestReadZFS(t *testing.T) {
+	s := &StorageCollector{}
+	metrics := s.readZFS(filepath.Join("testdata", "zfs"))
+	if !metrics.Available || metrics.UnhealthyPools != 1 || len(metrics.Pools) != 2 {
+		t.Fatalf("readZFS = %+v", metrics)
+	}
+	if metrics.ARC.SizeBytes != 3221225472 || metrics.ARC.HitRatio != 90 {
+		t.Errorf("arc = %+v", metrics.ARC)
+	}
+}
+
+func TestReadZFSHitRatioInterval(t *testing.T) {
+	dir := t.TempDir()
+	writeArc := func(hits, misses string) {
+		data := "13 1 0x01 2 96 1 1\nname type data\nhits 4 " + hits + "\nmisses 4 " + misses + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "arcstats"), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := &StorageCollector{}
+	steps := []struct {
+		hits, misses string
+		want         float64
+	}{
+		{"900", "100", 90},   // 首次采集使用累计值
+		{"930", "170", 30},   // 区间内 30 次命中、70 次未命中
+		{"930", "170", 30},   // 空闲区间沿用上一次的命中率
+		{"1030", "170", 100}, // 区间内全部命中
+	}
+	for i, step := range steps {
+		writeArc(step.hits, step.misses)
+		if got := s.readZFS(dir).ARC.HitRatio; got != step.want {
+			t.Errorf("step %d: hit ratio = %v, want %v", i, got, step.want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package collector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sudoersMaxDepth include 嵌套层数上限
+const sudoersMaxDepth = 8
+
+// sudoersParser 解析 sudoers 及其 include 文件中的用户授权
+type sudoersParser struct {
+	userAliases map[string][]string
+	specs       [][]string // 每条授权规则的用户列表
+	visited     map[string]bool
+}
+
+// readSudoers 返回被授权的用户（含 ALL 与 #uid）与组，只有主文件不可读时返回错误
+func readSudoers(path string) (map[string]bool, []string, error) {
+	p := &sudoersParser{
+		userAliases: make(map[string][]string),
+		visited:     make(map[string]bool),
+	}
+	if err := p.parseFile(path, 0); err != nil {
+		return nil, []string{}, err
+	}
+
+	users := make(map[string]bool)
+	groupSet := make(map[string]bool)
+	for _, spec := range p.specs {
+		for _, item := range p.expand(spec, 0) {
+			switch {
+			case strings.HasPrefix(item, "%:"):
+				groupSet[item[2:]] = true
+			case strings.HasPrefix(item, "%"):
+				groupSet[item[1:]] = true
+			default:
+				users[item] = true
+			}
+		}
+	}
+
+	groups := make([]string, 0, len(groupSet))
+	for group := range groupSet {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	return users, groups, nil
+}
+
+// parseFile 解析单个文件，处理 @include/@includedir（及旧式 #include）
+func (p *sudoersParser) parseFile(path string, depth int) error {
+	if depth > sudoersMaxDepth || p.visited[path] {
+		return nil
+	}
+	p.visited[path] = true
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var logical string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// 行尾反斜杠续行
+		if strings.HasSuffix(line, "\\") {
+			logical += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		logical += line
+		p.parseLine(filepath.Dir(path), logical, depth)
+		logical = ""
+	}
+	if logical != "" {
+		p.parseLine(filepath.Dir(path), logical, depth)
+	}
+	return scanner.Err()
+}
+
+// parseLine 解析一行（已合并续行）
+func (p *sudoersParser) parseLine(dir, line string, depth int) {
+	line = strings.TrimSpace(line)
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+
+	switch fields[0] {
+	case "@include", "#include":
+		if len(fields) > 1 {
+			p.parseFile(sudoersPath(dir, fields[1]), depth+1)
+		}
+		return
+	case "@includedir", "#includedir":
+		if len(fields) > 1 {
+			p.parseDir(sudoersPath(dir, fields[1]), depth+1)
+		}
+		return
+	}
+
+	line = stripSudoersComment(line)
+	fields = strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+
+	switch {
+	case fields[0] == "User_Alias":
+		// User_Alias NAME = a, b : NAME2 = c
+		for _, def := range strings.Split(strings.TrimSpace(line[len("User_Alias"):]), ":") {
+			name, members, ok := strings.Cut(def, "=")
+			if !ok {
+				continue
+			}
+			p.userAliases[strings.TrimSpace(name)] = splitSudoersList(members)
+		}
+	case strings.HasPrefix(fields[0], "Defaults"),
+		fields[0] == "Host_Alias", fields[0] == "Runas_Alias",
+		fields[0] == "Cmnd_Alias", fields[0] == "Cmd_Alias":
+		// 与用户授权无关
+	default:
+		// 用户规则: user_list host_list = [(runas)] commands
+		left, _, ok := strings.Cut(line, "=")
+		if !ok {
+			return
+		}
+		// 用户列表中逗号后可以有空格，归一化后第一个字段即用户列表
+		left = strings.Join(strings.Fields(strings.ReplaceAll(left, ",", " , ")), " ")
+		left = strings.ReplaceAll(left, " , ", ",")
+		userList := strings.Fields(left)
+		if len(userList) < 2 {
+			return
+		}
+		p.specs = append(p.specs, splitSudoersList(userList[0]))
+	}
+}
+
+// parseDir 按文件名顺序解析目录，与 sudo 一样跳过以 ~ 结尾或包含 . 的文件
+func (p *sudoersParser) parseDir(dir string, depth int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasSuffix(name, "~") || strings.Contains(name, ".") {
+			continue
+		}
+		p.parseFile(filepath.Join(dir, name), depth)
+	}
+}
+
+// expand 展开 User_Alias，忽略取反的条目
+func (p *sudoersParser) expand(items []string, depth int) []string {
+	var result []string
+	for _, item := range items {
+		if item == "" || strings.HasPrefix(item, "!") {
+			continue
+		}
+		if members, ok := p.userAliases[item]; ok && depth < sudoersMaxDepth {
+			result = append(result, p.expand(members, depth+1)...)
+			continue
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// stripSudoersComment 去掉注释，#后紧跟数字的是 UID 而不是注释
+func stripSudoersComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] != '#' {
+			continue
+		}
+		if i+1 < len(line) && line[i+1] >= '0' && line[i+1] <= '9' {
+			continue
+		}
+		return line[:i]
+	}
+	return line
+}
+
+// splitSudoersList 拆分逗号分隔的列表
+func splitSudoersList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// sudoersPath include 的相对路径相对于当前文件所在目录
+func sudoersPath(dir, path string) string {
+	path = strings.Trim(path, `"`)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -2,6 +2,7 @@ package collector
 
 import (
 	"host-monitor-agent/models"
+	"syscall"
 
 	"github.com/shirou/gopsutil/v3/net"
 )
@@ -10,43 +11,92 @@ import (
 type TCPCollector struct{}
 
 // Collect 采集TCP连接指标
+// 直接读取内核套接字表（netlink 优先，/proc/net/tcp 兜底），
+// 避免 net.Connections 遍历所有进程 fd 表带来的开销
 func (t *TCPCollector) Collect() (interface{}, error) {
+	entries, source, err := readSockets(syscall.IPPROTO_TCP, tcpAllStates)
+	if err != nil {
+		// 非 Linux 平台没有 netlink 与 procfs，退回 gopsutil
+		return collectTCPFromConnections()
+	}
+
+	metrics := models.TCPMetrics{Source: source}
+
+	// 统计各状态的连接数
+	for _, entry := range entries {
+		if entry.Family == syscall.AF_INET6 {
+			countTCPState(&metrics.IPv6, entry.State)
+		} else {
+			countTCPState(&metrics.IPv4, entry.State)
+		}
+		countTCPState(&metrics.TCPStateCounts, entry.State)
+	}
+
+	return metrics, nil
+}
+
+// countTCPState 按内核状态编号累加连接数
+func countTCPState(counts *models.TCPStateCounts, state uint8) {
+	counts.Total++
+
+	switch state {
+	case tcpEstablished:
+		counts.Established++
+	case tcpSynSent:
+		counts.SynSent++
+	case tcpSynRecv, tcpNewSynRecv:
+		counts.SynRecv++
+	case tcpFinWait1:
+		counts.FinWait1++
+	case tcpFinWait2:
+		counts.FinWait2++
+	case tcpTimeWait:
+		counts.TimeWait++
+	case tcpClose:
+		counts.Close++
+	case tcpCloseWait:
+		counts.CloseWait++
+	case tcpLastAck:
+		counts.LastAck++
+	case tcpListen:
+		counts.Listen++
+	case tcpClosing:
+		counts.Closing++
+	}
+}
+
+// collectTCPFromConnections 通过 gopsutil 统计连接状态（非 Linux 平台兜底）
+func collectTCPFromConnections() (interface{}, error) {
 	connections, err := net.Connections("tcp")
 	if err != nil {
 		return models.TCPMetrics{}, err
 	}
 
-	metrics := models.TCPMetrics{}
+	metrics := models.TCPMetrics{Source: "gopsutil"}
+
+	stateNumbers := map[string]uint8{
+		"ESTABLISHED": tcpEstablished,
+		"SYN_SENT":    tcpSynSent,
+		"SYN_RECV":    tcpSynRecv,
+		"FIN_WAIT1":   tcpFinWait1,
+		"FIN_WAIT2":   tcpFinWait2,
+		"TIME_WAIT":   tcpTimeWait,
+		"CLOSE":       tcpClose,
+		"CLOSE_WAIT":  tcpCloseWait,
+		"LAST_ACK":    tcpLastAck,
+		"LISTEN":      tcpListen,
+		"CLOSING":     tcpClosing,
+	}
 
-	// 统计各状态的连接数
 	for _, conn := range connections {
-		metrics.Total++
-
-		switch conn.Status {
-		case "ESTABLISHED":
-			metrics.Established++
-		case "SYN_SENT":
-			metrics.SynSent++
-		case "SYN_RECV":
-			metrics.SynRecv++
-		case "FIN_WAIT1":
-			metrics.FinWait1++
-		case "FIN_WAIT2":
-			metrics.FinWait2++
-		case "TIME_WAIT":
-			metrics.TimeWait++
-		case "CLOSE":
-			metrics.Close++
-		case "CLOSE_WAIT":
-			metrics.CloseWait++
-		case "LAST_ACK":
-			metrics.LastAck++
-		case "LISTEN":
-			metrics.Listen++
-		case "CLOSING":
-			metrics.Closing++
+		state := stateNumbers[conn.Status]
+		if conn.Family == syscall.AF_INET6 {
+			countTCPState(&metrics.IPv6, state)
+		} else {
+			countTCPState(&metrics.IPv4, state)
 		}
+		countTCPState(&metrics.TCPStateCounts, state)
 	}
 
 	return metrics, nil
-}
\ No newline at end of file
+}
@@ -16,7 +16,39 @@ type ServerConfig struct {
 
 // CollectorConfig 采集器配置
 type CollectorConfig struct {
-	Interval time.Duration `json:"interval"` // 采集间隔（秒）
+	Interval   time.Duration `json:"interval"`    // 采集间隔（秒）
+	NTPServers []string      `json:"ntp_servers"` // SNTP 对时检查的服务器（host 或 host:port），为空不检查
+	StateDir   string        `json:"state_dir"`   // 日志读取位置等持久化状态目录
+
+	BruteForce BruteForceConfig `json:"brute_force"` // SSH 暴力破解检测
+	Integrity  IntegrityConfig  `json:"integrity"`   // 关键文件完整性监控
+	Benchmark  BenchmarkConfig  `json:"benchmark"`   // YAML 合规基线检查
+}
+
+// BruteForceConfig 暴力破解检测与封禁配置
+type BruteForceConfig struct {
+	Threshold   int           `json:"threshold"`    // 窗口内失败尝试次数达到该值判定为恶意来源，0 关闭检测
+	Window      time.Duration `json:"window"`       // 失败次数统计窗口
+	BanDuration time.Duration `json:"ban_duration"` // 封禁时长，到期自动解除
+	Action      string        `json:"action"`       // exec/ipset/nftables
+	DryRun      bool          `json:"dry_run"`      // 只记录将要执行的封禁命令，不实际执行
+	ExecCommand string        `json:"exec_command"` // exec 动作执行的程序，参数为 ban|unban <ip> <秒数>
+	IPSetName   string        `json:"ipset_name"`   // ipset 动作使用的集合（需带 timeout 创建），IPv6 使用 <name>6
+	NFTSet      string        `json:"nft_set"`      // nftables 动作使用的集合 "<family> <table> <set>"，IPv6 使用 <set>6
+	Whitelist   []string      `json:"whitelist"`    // 永不封禁的地址或网段
+}
+
+// IntegrityConfig 文件完整性监控配置
+type IntegrityConfig struct {
+	Paths    []string      `json:"paths"`     // 监控的文件与目录（目录递归，不跟随符号链接），为空关闭
+	Interval time.Duration `json:"interval"`  // 扫描间隔，未变化的文件复用基线中的哈希
+	MaxFiles int           `json:"max_files"` // 单次扫描的文件数上限
+}
+
+// BenchmarkConfig 合规基线检查配置
+type BenchmarkConfig struct {
+	RulesPath string        `json:"rules_path"` // 规则文件或目录（*.yaml），不存在时关闭
+	Interval  time.Duration `json:"interval"`   // 检查间隔，每次检查重新加载规则
 }
 
 // DefaultConfig 默认配置
@@ -28,6 +60,36 @@ func DefaultConfig() *Config {
 		},
 		Collector: CollectorConfig{
 			Interval: 10 * time.Second, // 默认10秒采集一次
+			StateDir: "state",          // 与 PID、日志文件一样相对于工作目录
+			BruteForce: BruteForceConfig{
+				Threshold:   10,
+				Window:      10 * time.Minute,
+				BanDuration: time.Hour,
+				Action:      "ipset",
+				DryRun:      true, // 默认只记录不封禁
+				IPSetName:   "monitor-agent-block",
+				NFTSet:      "inet filter monitor_agent_block",
+				Whitelist:   []string{"127.0.0.0/8", "::1/128"},
+			},
+			Integrity: IntegrityConfig{
+				Paths: []string{
+					"/etc/passwd",
+					"/etc/shadow",
+					"/etc/group",
+					"/etc/sudoers",
+					"/etc/sudoers.d",
+					"/etc/ssh/sshd_config",
+					"/root/.ssh/authorized_keys",
+					"/usr/bin",
+					"/usr/sbin",
+				},
+				Interval: 5 * time.Minute,
+				MaxFiles: 50000,
+			},
+			Benchmark: BenchmarkConfig{
+				RulesPath: "rules",
+				Interval:  time.Hour,
+			},
 		},
 	}
-}
\ No newline at end of file
+}
@@ -69,7 +69,7 @@ func serve() {
 	cfg := config.DefaultConfig()
 
 	// 创建并启动缓存
-	metricsCache := cache.NewMetricsCache(cfg.Collector.Interval)
+	metricsCache := cache.NewMetricsCache(cfg.Collector)
 	metricsCache.Start()
 
 	// 设置路由
@@ -87,6 +87,10 @@ func serve() {
 		log.Printf("Host Monitor Agent starting on %s", addr)
 		log.Printf("Metrics collection interval: %v", cfg.Collector.Interval)
 		log.Printf("Access metrics at: http://%s/metrics", addr)
+		log.Printf("Recent events at: http://%s/events", addr)
+		log.Printf("Hostile IPs at: http://%s/security/hostile", addr)
+		log.Printf("Compliance results at: http://%s/compliance", addr)
+		log.Printf("Installed packages at: http://%s/packages", addr)
 		log.Printf("Health check at: http://%s/health", addr)
 
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -129,4 +133,4 @@ func serve() {
 			return
 		}
 	}
-}
\ No newline at end of file
+}
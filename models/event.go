@@ -0,0 +1,10 @@
+package models
+
+// Event 采集器在两次采集之间检测到的变化事件
+type Event struct {
+	Time       string            `json:"time"`
+	Source     string            `json:"source"` // 产生事件的采集器，如 listener
+	Type       string            `json:"type"`   // 事件类型，如 listener_opened
+	Message    string            `json:"message"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
@@ -2,21 +2,62 @@ package models
 
 // HostMetrics 主机所有监控指标
 type HostMetrics struct {
-	Timestamp      string           `json:"timestamp"`
-	Hostname       string           `json:"hostname"`
-	IntranetIPs    []string         `json:"intranet_ips"`
-	OS             string           `json:"os"`              // 操作系统发行版
-	KernelVersion  string           `json:"kernel_version"`  // 内核版本
-	Timezone       string           `json:"timezone"`        // 时区
-	Uptime         string           `json:"uptime"`          // 运行时间
-	CPU            CPUMetrics       `json:"cpu"`
-	Memory         MemoryMetrics    `json:"memory"`
-	Disk           []DiskMetrics    `json:"disk"`
-	Load           LoadMetrics      `json:"load"`
-	TCP            TCPMetrics       `json:"tcp"`
-	FileDescriptor FDMetrics        `json:"file_descriptor"`
-	Network        []NetworkMetrics `json:"network"`
-	Security       SecurityMetrics  `json:"security"`
+	Timestamp      string            `json:"timestamp"`
+	Hostname       string            `json:"hostname"`
+	IntranetIPs    []string          `json:"intranet_ips"`
+	OS             string            `json:"os"`             // 操作系统发行版
+	KernelVersion  string            `json:"kernel_version"` // 内核版本
+	Timezone       string            `json:"timezone"`       // 时区
+	Uptime         string            `json:"uptime"`         // 运行时间
+	Clock          ClockMetrics      `json:"clock"`
+	CPU            CPUMetrics        `json:"cpu"`
+	CPUFreq        CPUFreqMetrics    `json:"cpu_freq"`
+	Memory         MemoryMetrics     `json:"memory"`
+	Disk           []DiskMetrics     `json:"disk"`
+	NFS            []NFSMountMetrics `json:"nfs"`
+	Storage        StorageMetrics    `json:"storage"`
+	Load           LoadMetrics       `json:"load"`
+	Kernel         KernelMetrics     `json:"kernel"`
+	KernelLog      KernelLogMetrics  `json:"kernel_log"`
+	Sensors        SensorMetrics     `json:"sensors"`
+	TCP            TCPMetrics        `json:"tcp"`
+	NetStat        NetStatMetrics    `json:"netstat"`
+	Listeners      ListenerMetrics   `json:"listeners"`
+	SockStat       SockStatMetrics   `json:"sockstat"`
+	Conntrack      ConntrackMetrics  `json:"conntrack"`
+	FileDescriptor FDMetrics         `json:"file_descriptor"`
+	Network        []NetworkMetrics  `json:"network"`
+	Bonds          []BondMetrics     `json:"bonds"`
+	Cgroups        CgroupMetrics     `json:"cgroups"`
+	Docker         DockerMetrics     `json:"docker"`
+	Security       SecurityMetrics   `json:"security"`
+	Accounts       AccountMetrics    `json:"accounts"`
+	Logins         LoginMetrics      `json:"logins"`
+	Integrity      IntegrityMetrics  `json:"integrity"`
+	Compliance     ComplianceMetrics `json:"compliance"`
+	Packages       PackageMetrics    `json:"packages"`
+	Events         []Event           `json:"events"` // 本次采集产生的事件
+}
+
+// ClockMetrics 时钟同步状态
+type ClockMetrics struct {
+	Available       bool        `json:"available"`         // 是否可读取内核时钟状态（adjtimex）
+	Synced          bool        `json:"synced"`            // 内核认为时钟已同步
+	OffsetSeconds   float64     `json:"offset_seconds"`    // 内核PLL当前偏差
+	FrequencyPPM    float64     `json:"frequency_ppm"`     // 频率校正量
+	MaxErrorSeconds float64     `json:"max_error_seconds"` // 最大误差
+	EstErrorSeconds float64     `json:"est_error_seconds"` // 估计误差
+	NTP             []NTPOffset `json:"ntp"`               // SNTP 实测偏差（配置了服务器时）
+}
+
+// NTPOffset 通过 SNTP 查询测得的本机时钟偏差
+type NTPOffset struct {
+	Server        string  `json:"server"`
+	OffsetSeconds float64 `json:"offset_seconds"` // 正值表示本机时钟落后于服务器
+	RTTSeconds    float64 `json:"rtt_seconds"`
+	Stratum       uint8   `json:"stratum"`
+	QueriedAt     string  `json:"queried_at"`
+	Error         string  `json:"error,omitempty"`
 }
 
 // CPUMetrics CPU监控指标
@@ -25,6 +66,37 @@ type CPUMetrics struct {
 	CoreCount    int     `json:"core_count"`
 }
 
+// CPUFreqMetrics CPU频率、调速器与 C-state 驻留分布
+type CPUFreqMetrics struct {
+	Available      bool              `json:"available"` // 是否存在 cpufreq 接口（虚拟机通常没有）
+	Driver         string            `json:"driver"`    // 如 intel_pstate/acpi-cpufreq
+	Governor       string            `json:"governor"`  // 所有核心一致时为调速器名，否则为 mixed
+	GovernorCounts map[string]int    `json:"governor_counts"`
+	AvgMHz         float64           `json:"avg_mhz"`
+	MinMHz         float64           `json:"min_mhz"` // 当前频率最低的核心
+	MaxMHz         float64           `json:"max_mhz"` // 当前频率最高的核心
+	Cores          []CPUCoreFreq     `json:"cores"`
+	IdleDriver     string            `json:"idle_driver"` // 如 intel_idle/acpi_idle
+	CStates        []CStateResidency `json:"cstates"`
+}
+
+// CPUCoreFreq 单个逻辑CPU的频率
+type CPUCoreFreq struct {
+	CPU        int     `json:"cpu"`
+	CurrentMHz float64 `json:"current_mhz"`
+	MinMHz     float64 `json:"min_mhz"` // scaling_min_freq
+	MaxMHz     float64 `json:"max_mhz"` // scaling_max_freq
+	Governor   string  `json:"governor"`
+}
+
+// CStateResidency 所有CPU汇总的 C-state 驻留情况
+type CStateResidency struct {
+	Name             string  `json:"name"`
+	Usage            uint64  `json:"usage"`             // 累计进入次数
+	TimeSeconds      float64 `json:"time_seconds"`      // 累计驻留时间
+	ResidencyPercent float64 `json:"residency_percent"` // 两次采集间驻留时间占比
+}
+
 // MemoryMetrics 内存监控指标
 type MemoryMetrics struct {
 	Total        float64 `json:"total_gb"`
@@ -35,11 +107,130 @@ type MemoryMetrics struct {
 // DiskMetrics 磁盘监控指标
 type DiskMetrics struct {
 	MountPoint   string  `json:"mount_point"`
+	Device       string  `json:"device"`
+	FSType       string  `json:"fs_type"`
+	Server       string  `json:"server,omitempty"` // 网络文件系统的服务端
+	Stale        bool    `json:"stale"`            // statfs 超时，挂载已失效（如 NFS 服务端无响应），容量未知
 	Total        float64 `json:"total_gb"`
 	Used         float64 `json:"used_gb"`
 	UsagePercent float64 `json:"usage_percent"`
 }
 
+// NFSMountMetrics NFS 客户端单个挂载点的统计（/proc/self/mountstats），累计值自挂载起
+type NFSMountMetrics struct {
+	MountPoint    string      `json:"mount_point"`
+	Device        string      `json:"device"` // server:/export
+	Server        string      `json:"server"`
+	FSType        string      `json:"fs_type"`
+	Version       string      `json:"version"`
+	Ops           uint64      `json:"ops"`
+	Retransmits   uint64      `json:"retransmits"`    // RPC 重传次数
+	MajorTimeouts uint64      `json:"major_timeouts"` // 主超时次数，hard 挂载下会出现 "server not responding"
+	Errors        uint64      `json:"errors"`         // 返回错误的操作数（statvers 1.1 及较新内核）
+	ReadBytes     uint64      `json:"read_bytes"`     // 从服务端读取的字节数
+	WriteBytes    uint64      `json:"write_bytes"`    // 写入服务端的字节数
+	AvgRTTMs      float64     `json:"avg_rtt_ms"`     // 自挂载起的平均 RTT
+	AvgExecMs     float64     `json:"avg_exec_ms"`    // 自挂载起的平均执行时间（含排队）
+	OpsPerSec     float64     `json:"ops_per_sec"`
+	RetransPerSec float64     `json:"retrans_per_sec"`
+	IntervalRTTMs float64     `json:"interval_rtt_ms"` // 两次采集之间的平均 RTT
+	Operations    []NFSOpStat `json:"operations"`      // 按操作数从多到少
+}
+
+// NFSOpStat 单个 NFS 操作的统计
+type NFSOpStat struct {
+	Op            string  `json:"op"`
+	Ops           uint64  `json:"ops"`
+	Retransmits   uint64  `json:"retransmits"`
+	MajorTimeouts uint64  `json:"major_timeouts"`
+	Errors        uint64  `json:"errors"`
+	AvgRTTMs      float64 `json:"avg_rtt_ms"`
+	AvgExecMs     float64 `json:"avg_exec_ms"`
+}
+
+// StorageMetrics 软 RAID、LVM thin-pool 与 ZFS 健康状态
+type StorageMetrics struct {
+	MDArrays       []MDArray  `json:"md_arrays"`
+	DegradedArrays int        `json:"degraded_arrays"`
+	MDStatError    string     `json:"mdstat_error,omitempty"` // 读取 /proc/mdstat 失败原因，未加载 md 模块时为空
+	ThinPools      []ThinPool `json:"thin_pools"`
+	ThinPoolError  string     `json:"thin_pool_error,omitempty"` // 读取 device-mapper 状态失败原因（需要 root）
+	ZFS            ZFSMetrics `json:"zfs"`
+}
+
+// MDArray md 软 RAID 阵列（/proc/mdstat）
+type MDArray struct {
+	Name              string     `json:"name"`
+	State             string     `json:"state"` // active/inactive
+	ReadOnly          bool       `json:"read_only"`
+	Level             string     `json:"level"` // raid0/raid1/raid5/raid6/raid10/linear
+	SizeBytes         uint64     `json:"size_bytes"`
+	Devices           int        `json:"devices"`        // 阵列应有的成员数
+	ActiveDevices     int        `json:"active_devices"` // 正常工作的成员数
+	Status            string     `json:"status"`         // 如 UU、U_，_ 为缺失的成员
+	Degraded          bool       `json:"degraded"`
+	Failed            []string   `json:"failed"` // 故障成员
+	Spares            int        `json:"spares"`
+	Members           []MDMember `json:"members"`
+	SyncAction        string     `json:"sync_action,omitempty"` // resync/recovery/reshape/check/repair
+	SyncPending       bool       `json:"sync_pending"`          // 同步被推迟（DELAYED/PENDING）
+	SyncProgress      float64    `json:"sync_progress"`         // 百分比
+	SyncFinishMinutes float64    `json:"sync_finish_minutes"`
+	SyncSpeedKBps     uint64     `json:"sync_speed_kbps"`
+}
+
+// MDMember md 阵列成员
+type MDMember struct {
+	Device      string `json:"device"`
+	Slot        int    `json:"slot"`
+	State       string `json:"state"` // in_sync/faulty/spare/journal/replacement
+	WriteMostly bool   `json:"write_mostly"`
+}
+
+// ThinPool LVM thin-pool 使用情况
+type ThinPool struct {
+	Name               string  `json:"name"` // device-mapper 设备名
+	VG                 string  `json:"vg"`
+	LV                 string  `json:"lv"`
+	Mode               string  `json:"mode"` // rw/ro/out_of_data_space/fail
+	NeedsCheck         bool    `json:"needs_check"`
+	DataTotalBytes     uint64  `json:"data_total_bytes"`
+	DataUsedBytes      uint64  `json:"data_used_bytes"`
+	DataPercent        float64 `json:"data_percent"`
+	MetadataTotalBytes uint64  `json:"metadata_total_bytes"`
+	MetadataUsedBytes  uint64  `json:"metadata_used_bytes"`
+	MetadataPercent    float64 `json:"metadata_percent"` // 元数据耗尽会导致池只读，需单独关注
+}
+
+// ZFSMetrics ZFS 存储池与 ARC 状态（/proc/spl/kstat/zfs）
+type ZFSMetrics struct {
+	Available      bool      `json:"available"` // 已加载 zfs 模块
+	Pools          []ZFSPool `json:"pools"`
+	UnhealthyPools int       `json:"unhealthy_pools"`
+	ARC            ZFSARC    `json:"arc"`
+}
+
+// ZFSPool ZFS 存储池
+type ZFSPool struct {
+	Name    string `json:"name"`
+	State   string `json:"state"` // ONLINE/DEGRADED/FAULTED/OFFLINE/UNAVAIL/REMOVED/SUSPENDED
+	Healthy bool   `json:"healthy"`
+}
+
+// ZFSARC ZFS ARC 缓存统计
+type ZFSARC struct {
+	SizeBytes   uint64  `json:"size_bytes"`
+	TargetBytes uint64  `json:"target_bytes"` // 当前目标大小（c）
+	MinBytes    uint64  `json:"min_bytes"`
+	MaxBytes    uint64  `json:"max_bytes"`
+	Hits        uint64  `json:"hits"`
+	Misses      uint64  `json:"misses"`
+	HitRatio    float64 `json:"hit_ratio"` // 两次采集之间的命中率（百分比）
+	L2SizeBytes uint64  `json:"l2_size_bytes"`
+	L2Hits      uint64  `json:"l2_hits"`
+	L2Misses    uint64  `json:"l2_misses"`
+}
+
 // LoadMetrics 负载监控指标
 type LoadMetrics struct {
 	Load1  float64 `json:"load1"`
@@ -47,10 +238,126 @@ type LoadMetrics struct {
 	Load15 float64 `json:"load15"`
 }
 
+// KernelMetrics 内核活动计数器（/proc/stat、/proc/vmstat、/proc/interrupts、/proc/softirqs）
+type KernelMetrics struct {
+	ContextSwitches uint64        `json:"context_switches"`
+	Interrupts      uint64        `json:"interrupts"`
+	SoftIRQs        uint64        `json:"softirqs"`
+	Forks           uint64        `json:"forks"`
+	ProcsRunning    uint64        `json:"procs_running"` // 运行队列中的进程数
+	ProcsBlocked    uint64        `json:"procs_blocked"` // 等待IO的进程数
+	PageFaults      uint64        `json:"page_faults"`
+	MajorFaults     uint64        `json:"major_faults"`
+	MinorFaults     uint64        `json:"minor_faults"`
+	PgScan          uint64        `json:"pgscan"`  // kswapd 与直接回收扫描页数
+	PgSteal         uint64        `json:"pgsteal"` // kswapd 与直接回收回收页数
+	OOMKills        uint64        `json:"oom_kills"`
+	Rates           KernelRates   `json:"rates"`            // 两次采集间的每秒速率
+	IRQsPerCPU      []float64     `json:"irqs_per_cpu"`     // 每个CPU每秒硬中断数
+	IRQs            []IRQStat     `json:"irqs"`             // 区间内有中断的 IRQ
+	SoftIRQsPerCPU  []SoftIRQStat `json:"softirqs_per_cpu"` // 按软中断类型的每CPU分布
+}
+
+// KernelLogMetrics 内核日志中的异常事件
+type KernelLogMetrics struct {
+	Available    bool              `json:"available"`
+	Source       string            `json:"source"`        // kmsg/dmesg
+	Counts       map[string]uint64 `json:"counts"`        // 按类型的本次开机以来累计次数
+	Lost         uint64            `json:"lost"`          // 读取前已被环形缓冲区覆盖的记录数
+	RecentEvents []KernelLogEvent  `json:"recent_events"` // 最近的事件（新的在前）
+	Error        string            `json:"error,omitempty"`
+}
+
+// KernelLogEvent 单条被识别的内核消息
+type KernelLogEvent struct {
+	Type       string            `json:"type"` // oom_kill/hung_task/soft_lockup/hard_lockup/hardware_error/fs_error/link_down/segfault/kernel_oops/kernel_panic
+	Time       string            `json:"time"` // 由开机时间与内核时间戳换算
+	Priority   int               `json:"priority"`
+	Message    string            `json:"message"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// KernelRates 内核活动每秒速率
+type KernelRates struct {
+	ContextSwitchesPerSec float64 `json:"context_switches_per_sec"`
+	InterruptsPerSec      float64 `json:"interrupts_per_sec"`
+	SoftIRQsPerSec        float64 `json:"softirqs_per_sec"`
+	ForksPerSec           float64 `json:"forks_per_sec"`
+	MajorFaultsPerSec     float64 `json:"major_faults_per_sec"`
+	MinorFaultsPerSec     float64 `json:"minor_faults_per_sec"`
+	PgScanPerSec          float64 `json:"pgscan_per_sec"`
+	PgStealPerSec         float64 `json:"pgsteal_per_sec"`
+	OOMKillsPerSec        float64 `json:"oom_kills_per_sec"`
+}
+
+// IRQStat 单个硬中断的每CPU速率
+type IRQStat struct {
+	IRQ          string    `json:"irq"`
+	Device       string    `json:"device"`
+	PerSec       float64   `json:"per_sec"`
+	PerCPUPerSec []float64 `json:"per_cpu_per_sec"`
+}
+
+// SoftIRQStat 单类软中断的每CPU速率
+type SoftIRQStat struct {
+	Name         string    `json:"name"` // NET_RX/NET_TX/TIMER...
+	PerSec       float64   `json:"per_sec"`
+	PerCPUPerSec []float64 `json:"per_cpu_per_sec"`
+}
+
+// SensorMetrics 硬件温度、风扇与电压传感器
+type SensorMetrics struct {
+	Temperatures         []TemperatureSensor `json:"temperatures"`
+	Fans                 []FanSensor         `json:"fans"`
+	Voltages             []VoltageSensor     `json:"voltages"`
+	CPUThrottle          []CPUThrottle       `json:"cpu_throttle"`
+	CoreThrottleTotal    uint64              `json:"core_throttle_total"`    // 所有核心降频次数之和
+	PackageThrottleTotal uint64              `json:"package_throttle_total"` // 按物理封装去重后的降频次数之和
+}
+
+// TemperatureSensor 温度传感器（摄氏度）
+type TemperatureSensor struct {
+	Chip        string  `json:"chip"`   // hwmon 芯片名或 thermal zone 类型
+	Sensor      string  `json:"sensor"` // 传感器标签
+	Celsius     float64 `json:"celsius"`
+	MaxCelsius  float64 `json:"max_celsius,omitempty"`
+	CritCelsius float64 `json:"crit_celsius,omitempty"`
+}
+
+// FanSensor 风扇转速
+type FanSensor struct {
+	Chip   string `json:"chip"`
+	Sensor string `json:"sensor"`
+	RPM    uint64 `json:"rpm"`
+}
+
+// VoltageSensor 电压传感器
+type VoltageSensor struct {
+	Chip   string  `json:"chip"`
+	Sensor string  `json:"sensor"`
+	Volts  float64 `json:"volts"`
+}
+
+// CPUThrottle 单个逻辑CPU的温控降频计数
+type CPUThrottle struct {
+	CPU                  int    `json:"cpu"`
+	CoreThrottleCount    uint64 `json:"core_throttle_count"`
+	PackageThrottleCount uint64 `json:"package_throttle_count"`
+}
+
 // IOMetrics IO监控指标（已移除，IO统计意义不大）
 
 // TCPMetrics TCP连接监控指标
+// 顶层状态计数为 IPv4 与 IPv6 之和
 type TCPMetrics struct {
+	TCPStateCounts
+	IPv4   TCPStateCounts `json:"ipv4"`
+	IPv6   TCPStateCounts `json:"ipv6"`
+	Source string         `json:"source"` // 数据来源：netlink/procfs/netlink+procfs/gopsutil
+}
+
+// TCPStateCounts 各状态TCP连接数
+type TCPStateCounts struct {
 	Established uint64 `json:"established"`
 	SynSent     uint64 `json:"syn_sent"`
 	SynRecv     uint64 `json:"syn_recv"`
@@ -65,6 +372,121 @@ type TCPMetrics struct {
 	Total       uint64 `json:"total"`
 }
 
+// ListenerMetrics 监听套接字清单
+type ListenerMetrics struct {
+	Total   int            `json:"total"`
+	Source  string         `json:"source"` // 数据来源：netlink/procfs/netlink+procfs
+	Sockets []ListenSocket `json:"sockets"`
+}
+
+// ListenSocket 单个监听中的 TCP/UDP 套接字
+type ListenSocket struct {
+	Protocol       string `json:"protocol"` // tcp/udp
+	Family         string `json:"family"`   // ipv4/ipv6
+	Address        string `json:"address"`
+	Port           uint16 `json:"port"`
+	PID            int32  `json:"pid"` // 0 表示无法确定所属进程
+	Process        string `json:"process"`
+	AcceptQueue    uint32 `json:"accept_queue"`     // 当前 accept 队列长度（仅TCP）
+	AcceptQueueMax uint32 `json:"accept_queue_max"` // accept 队列上限（仅TCP，procfs 下为0）
+	Ephemeral      bool   `json:"ephemeral"`        // 端口位于临时端口范围内（仅UDP），可能是未 connect 的客户端套接字
+}
+
+// SockStatMetrics 套接字汇总（/proc/net/sockstat、/proc/net/sockstat6）
+type SockStatMetrics struct {
+	SocketsUsed    uint64  `json:"sockets_used"`
+	TCPInUse       uint64  `json:"tcp_inuse"`
+	TCPOrphan      uint64  `json:"tcp_orphan"`    // 孤儿连接数
+	TCPTimeWait    uint64  `json:"tcp_time_wait"` // time-wait 桶数
+	TCPAlloc       uint64  `json:"tcp_alloc"`
+	TCPMemPages    uint64  `json:"tcp_mem_pages"`    // TCP 占用内存（页）
+	TCPMemLow      uint64  `json:"tcp_mem_low"`      // tcp_mem 下限（页）
+	TCPMemPressure uint64  `json:"tcp_mem_pressure"` // tcp_mem 压力阈值（页）
+	TCPMemHigh     uint64  `json:"tcp_mem_high"`     // tcp_mem 上限（页）
+	TCPMemPercent  float64 `json:"tcp_mem_percent"`  // 占 tcp_mem 上限的百分比
+	OrphanMax      uint64  `json:"tcp_max_orphans"`  // net.ipv4.tcp_max_orphans
+	OrphanPercent  float64 `json:"tcp_orphan_percent"`
+	UDPInUse       uint64  `json:"udp_inuse"`
+	UDPMemPages    uint64  `json:"udp_mem_pages"`
+	RawInUse       uint64  `json:"raw_inuse"`
+	TCP6InUse      uint64  `json:"tcp6_inuse"`
+	UDP6InUse      uint64  `json:"udp6_inuse"`
+	Raw6InUse      uint64  `json:"raw6_inuse"`
+}
+
+// ConntrackMetrics 连接跟踪表使用情况
+type ConntrackMetrics struct {
+	Available    bool              `json:"available"` // nf_conntrack 模块是否加载
+	Count        uint64            `json:"count"`
+	Max          uint64            `json:"max"`
+	UsagePercent float64           `json:"usage_percent"`
+	Stats        ConntrackStats    `json:"stats"`   // 所有CPU汇总
+	PerCPU       []ConntrackStats  `json:"per_cpu"` // 按CPU统计
+	ByProtocol   map[string]uint64 `json:"by_protocol,omitempty"`
+	ByState      map[string]uint64 `json:"by_state,omitempty"` // TCP 连接状态分布
+}
+
+// ConntrackStats 连接跟踪统计（/proc/net/stat/nf_conntrack）
+type ConntrackStats struct {
+	CPU           int    `json:"cpu"` // 汇总时为 -1
+	Found         uint64 `json:"found"`
+	Invalid       uint64 `json:"invalid"`
+	Insert        uint64 `json:"insert"`
+	InsertFailed  uint64 `json:"insert_failed"`
+	Drop          uint64 `json:"drop"`
+	EarlyDrop     uint64 `json:"early_drop"`
+	SearchRestart uint64 `json:"search_restart"`
+}
+
+// NetStatMetrics 内核 TCP/UDP 协议计数器（/proc/net/snmp、/proc/net/netstat）
+type NetStatMetrics struct {
+	TCP   TCPCounters  `json:"tcp"`
+	UDP   UDPCounters  `json:"udp"`
+	Rates NetStatRates `json:"rates"` // 两次采集间的每秒速率
+}
+
+// TCPCounters TCP协议累计计数器
+type TCPCounters struct {
+	ActiveOpens     uint64 `json:"active_opens"`  // 主动建连次数
+	PassiveOpens    uint64 `json:"passive_opens"` // 被动建连次数
+	AttemptFails    uint64 `json:"attempt_fails"` // 建连失败次数
+	EstabResets     uint64 `json:"estab_resets"`  // 已建立连接收到的重置（入向RST）
+	OutRsts         uint64 `json:"out_rsts"`      // 发出的RST段数
+	InSegs          uint64 `json:"in_segs"`
+	OutSegs         uint64 `json:"out_segs"`
+	RetransSegs     uint64 `json:"retrans_segs"` // 重传段数
+	InErrs          uint64 `json:"in_errs"`
+	ListenOverflows uint64 `json:"listen_overflows"` // accept队列溢出次数
+	ListenDrops     uint64 `json:"listen_drops"`     // 监听套接字丢弃的SYN
+	SyncookiesSent  uint64 `json:"syncookies_sent"`
+	Timeouts        uint64 `json:"timeouts"` // RTO超时次数
+}
+
+// UDPCounters UDP协议累计计数器
+type UDPCounters struct {
+	InDatagrams  uint64 `json:"in_datagrams"`
+	OutDatagrams uint64 `json:"out_datagrams"`
+	NoPorts      uint64 `json:"no_ports"`
+	InErrors     uint64 `json:"in_errors"`
+	RcvbufErrors uint64 `json:"rcvbuf_errors"` // 接收缓冲区满导致的丢包
+	SndbufErrors uint64 `json:"sndbuf_errors"`
+}
+
+// NetStatRates 协议计数器每秒速率
+type NetStatRates struct {
+	ActiveOpensPerSec     float64 `json:"active_opens_per_sec"`
+	PassiveOpensPerSec    float64 `json:"passive_opens_per_sec"`
+	EstabResetsPerSec     float64 `json:"estab_resets_per_sec"`
+	OutRstsPerSec         float64 `json:"out_rsts_per_sec"`
+	RetransSegsPerSec     float64 `json:"retrans_segs_per_sec"`
+	ListenOverflowsPerSec float64 `json:"listen_overflows_per_sec"`
+	ListenDropsPerSec     float64 `json:"listen_drops_per_sec"`
+	SyncookiesSentPerSec  float64 `json:"syncookies_sent_per_sec"`
+	TimeoutsPerSec        float64 `json:"timeouts_per_sec"`
+	UDPInErrorsPerSec     float64 `json:"udp_in_errors_per_sec"`
+	UDPRcvbufErrorsPerSec float64 `json:"udp_rcvbuf_errors_per_sec"`
+}
+
 // FDMetrics 文件描述符监控指标
 type FDMetrics struct {
 	Allocated uint64 `json:"allocated"` // 已分配的文件描述符数
@@ -73,12 +495,400 @@ type FDMetrics struct {
 
 // NetworkMetrics 网络流量监控指标
 type NetworkMetrics struct {
-	Interface string `json:"interface"`
-	BytesSent uint64 `json:"bytes_sent"`
-	BytesRecv uint64 `json:"bytes_recv"`
+	Interface      string  `json:"interface"`
+	MACAddress     string  `json:"mac_address"`
+	OperState      string  `json:"oper_state"` // up/down/unknown/dormant...
+	SpeedMbps      int64   `json:"speed_mbps"` // 链路速率，-1 表示未知
+	Duplex         string  `json:"duplex"`     // full/half/unknown
+	MTU            uint64  `json:"mtu"`
+	CarrierChanges uint64  `json:"carrier_changes"` // 链路状态变化次数
+	BytesSent      uint64  `json:"bytes_sent"`
+	BytesRecv      uint64  `json:"bytes_recv"`
+	PacketsSent    uint64  `json:"packets_sent"`
+	PacketsRecv    uint64  `json:"packets_recv"`
+	ErrorsIn       uint64  `json:"errors_in"`
+	ErrorsOut      uint64  `json:"errors_out"`
+	DropsIn        uint64  `json:"drops_in"`
+	DropsOut       uint64  `json:"drops_out"`
+	FifoIn         uint64  `json:"fifo_in"`
+	FifoOut        uint64  `json:"fifo_out"`
+	Multicast      uint64  `json:"multicast"`
+	Rates          NetRate `json:"rates"` // 两次采集间的每秒速率
+}
+
+// NetRate 网络接口每秒速率
+type NetRate struct {
+	BytesSentPerSec   float64 `json:"bytes_sent_per_sec"`
+	BytesRecvPerSec   float64 `json:"bytes_recv_per_sec"`
+	PacketsSentPerSec float64 `json:"packets_sent_per_sec"`
+	PacketsRecvPerSec float64 `json:"packets_recv_per_sec"`
+	ErrorsInPerSec    float64 `json:"errors_in_per_sec"`
+	ErrorsOutPerSec   float64 `json:"errors_out_per_sec"`
+	DropsInPerSec     float64 `json:"drops_in_per_sec"`
+	DropsOutPerSec    float64 `json:"drops_out_per_sec"`
+}
+
+// BondMetrics bonding/team 聚合接口的健康状态
+type BondMetrics struct {
+	Name              string      `json:"name"`
+	Driver            string      `json:"driver"`       // bonding 或 team
+	Mode              string      `json:"mode"`         // active-backup、802.3ad、balance-rr、activebackup、lacp...
+	ActiveSlave       string      `json:"active_slave"` // 仅主备类模式
+	MIIStatus         string      `json:"mii_status"`   // up/down
+	Slaves            int         `json:"slaves"`       // 已加入的成员数
+	UpSlaves          int         `json:"up_slaves"`    // 链路正常、可承载流量的成员数
+	ReducedRedundancy bool        `json:"reduced_redundancy"`
+	Members           []BondSlave `json:"members"`
+	Error             string      `json:"error,omitempty"`
+}
+
+// BondSlave 聚合接口的成员接口
+type BondSlave struct {
+	Interface    string `json:"interface"`
+	MIIStatus    string `json:"mii_status"` // up/down/going down/going back
+	SpeedMbps    int64  `json:"speed_mbps"` // 链路速率，-1 表示未知
+	Duplex       string `json:"duplex"`
+	LinkFailures uint64 `json:"link_failures"`           // 链路故障次数
+	AggregatorID int    `json:"aggregator_id,omitempty"` // 802.3ad 所属聚合组
+	Active       bool   `json:"active"`                  // 当前参与转发
+}
+
+// CgroupMetrics 容器、Pod 与 systemd slice 的资源使用
+type CgroupMetrics struct {
+	Version int           `json:"version"` // cgroup 版本：1 或 2
+	Groups  []CgroupStats `json:"groups"`
+}
+
+// CgroupStats 单个 cgroup 的资源使用
+type CgroupStats struct {
+	Path             string  `json:"path"`
+	Type             string  `json:"type"` // container/pod/slice
+	Name             string  `json:"name"`
+	ContainerID      string  `json:"container_id,omitempty"`
+	Runtime          string  `json:"runtime,omitempty"` // docker/containerd/crio/podman
+	PodUID           string  `json:"pod_uid,omitempty"`
+	QoSClass         string  `json:"qos_class,omitempty"` // guaranteed/burstable/besteffort
+	CPUUsageSeconds  float64 `json:"cpu_usage_seconds"`
+	CPUUsagePercent  float64 `json:"cpu_usage_percent"` // 两次采集间的平均值，100 表示一个核
+	NrPeriods        uint64  `json:"nr_periods"`
+	NrThrottled      uint64  `json:"nr_throttled"`
+	ThrottledSeconds float64 `json:"throttled_seconds"`
+	MemoryBytes      uint64  `json:"memory_bytes"`
+	MemoryMaxBytes   uint64  `json:"memory_max_bytes"` // 0 表示不限制
+	MemoryOOM        uint64  `json:"memory_oom"`
+	MemoryOOMKill    uint64  `json:"memory_oom_kill"`
+	IOReadBytes      uint64  `json:"io_read_bytes"`
+	IOWriteBytes     uint64  `json:"io_write_bytes"`
+	IOReadOps        uint64  `json:"io_read_ops"`
+	IOWriteOps       uint64  `json:"io_write_ops"`
+	PidsCurrent      uint64  `json:"pids_current"`
+	PidsMax          uint64  `json:"pids_max"` // 0 表示不限制
+}
+
+// DockerMetrics Docker Engine 状态
+type DockerMetrics struct {
+	Available           bool              `json:"available"` // docker.sock 是否可访问
+	ServerVersion       string            `json:"server_version"`
+	ContainersTotal     int               `json:"containers_total"`
+	ContainersRunning   int               `json:"containers_running"`
+	ContainersPaused    int               `json:"containers_paused"`
+	ContainersStopped   int               `json:"containers_stopped"`
+	ContainersUnhealthy int               `json:"containers_unhealthy"`
+	Images              int               `json:"images"`
+	Volumes             int               `json:"volumes"`
+	ImagesSizeBytes     int64             `json:"images_size_bytes"`
+	ContainersSizeBytes int64             `json:"containers_size_bytes"` // 容器可写层
+	VolumesSizeBytes    int64             `json:"volumes_size_bytes"`
+	BuildCacheSizeBytes int64             `json:"build_cache_size_bytes"`
+	Containers          []DockerContainer `json:"containers"`
+	Error               string            `json:"error,omitempty"` // 获取容器列表失败原因，守护进程信息仍然有效
+}
+
+// DockerContainer 单个容器状态
+type DockerContainer struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	State        string `json:"state"`  // running/exited/restarting/paused...
+	Health       string `json:"health"` // healthy/unhealthy/starting，未配置健康检查为空
+	Status       string `json:"status"` // 人类可读状态，如 "Up 3 hours"
+	RestartCount int    `json:"restart_count"`
+	ExitCode     int    `json:"exit_code"`
+	Created      string `json:"created"`
 }
 
 // SecurityMetrics 安全监控指标
 type SecurityMetrics struct {
-	LoginFailures uint64 `json:"login_failures"` // 登录失败次数
-}
\ No newline at end of file
+	LogFile             string            `json:"log_file"`              // 当前读取的认证日志
+	LoginFailures       uint64            `json:"login_failures"`        // 采集器启动以来的登录失败次数（单调递增）
+	LoginFailuresWindow uint64            `json:"login_failures_window"` // 最近一个窗口内的登录失败次数
+	WindowSeconds       int               `json:"window_seconds"`
+	SSH                 SSHAuthMetrics    `json:"ssh"`
+	Sudo                PrivilegeMetrics  `json:"sudo"`
+	Su                  PrivilegeMetrics  `json:"su"`
+	TopAttackers        []AuthSource      `json:"top_attackers"`    // 窗口内尝试次数最多的来源IP
+	TopTargetUsers      []AuthTarget      `json:"top_target_users"` // 窗口内被尝试最多的用户
+	BruteForce          BruteForceMetrics `json:"brute_force"`
+	SSHDConfig          SSHDConfigMetrics `json:"sshd_config"` // sshd 配置加固检查
+}
+
+// SSHDConfigMetrics sshd 配置加固检查结果
+type SSHDConfigMetrics struct {
+	Available bool              `json:"available"` // 配置文件可读
+	Files     []string          `json:"files"`     // 解析的配置文件（含 Include）
+	Passed    int               `json:"passed"`
+	Failed    int               `json:"failed"`
+	Checks    []ComplianceCheck `json:"checks"`
+}
+
+// ComplianceCheck 单项合规检查结果
+type ComplianceCheck struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"` // high/medium/low
+	Passed   bool   `json:"passed"`
+	Value    string `json:"value"`    // 实际生效的值
+	Expected string `json:"expected"` // 期望的值
+}
+
+// AccountMetrics 本地用户与特权审计
+type AccountMetrics struct {
+	ShadowReadable  bool          `json:"shadow_readable"`  // /etc/shadow 需要 root 权限
+	SudoersReadable bool          `json:"sudoers_readable"` // /etc/sudoers 需要 root 权限
+	Users           int           `json:"users"`            // /etc/passwd 中的账户数
+	Groups          int           `json:"groups"`
+	LoginUsers      []UserAccount `json:"login_users"`     // 有登录 shell、UID 0 或可 sudo 的账户
+	UID0Accounts    []string      `json:"uid0_accounts"`   // root 以外 UID 为 0 的账户
+	EmptyPassword   []string      `json:"empty_password"`  // 密码为空、无需密码即可登录的账户
+	LockedPassword  []string      `json:"locked_password"` // 密码被锁定或未设置的账户
+	SudoUsers       []string      `json:"sudo_users"`      // 可通过 sudo 提权的用户（含组授权展开）
+	SudoGroups      []string      `json:"sudo_groups"`     // sudoers 中授权的组
+	UsersAdded      []string      `json:"users_added"`     // 与上次采集相比新增的用户
+	UsersRemoved    []string      `json:"users_removed"`
+	GroupsAdded     []string      `json:"groups_added"`
+	GroupsRemoved   []string      `json:"groups_removed"`
+}
+
+// UserAccount 单个账户的审计信息
+type UserAccount struct {
+	Name            string   `json:"name"`
+	UID             int      `json:"uid"`
+	GID             int      `json:"gid"`
+	Home            string   `json:"home"`
+	Shell           string   `json:"shell"`
+	LoginShell      bool     `json:"login_shell"`
+	Password        string   `json:"password"`          // set/empty/locked/unknown（shadow 不可读）
+	PasswordAgeDays int      `json:"password_age_days"` // 距上次修改的天数，未知为 -1
+	PasswordMaxDays int      `json:"password_max_days"` // 密码有效期，未设置为 -1
+	Sudo            bool     `json:"sudo"`
+	Groups          []string `json:"groups"` // 所属组（含主组）
+}
+
+// LoginMetrics 登录会话与登录历史（utmp/wtmp/btmp）
+type LoginMetrics struct {
+	Available      bool           `json:"available"` // utmp 可读
+	Sessions       []LoginSession `json:"sessions"`  // 当前登录会话
+	Users          int            `json:"users"`     // 当前登录的不同用户数
+	LastBoot       string         `json:"last_boot"`
+	RecentLogins   []LoginRecord  `json:"recent_logins"`   // wtmp 中最近的登录（新的在前）
+	RecentReboots  []BootRecord   `json:"recent_reboots"`  // wtmp 中最近的重启与关机（新的在前）
+	BtmpAvailable  bool           `json:"btmp_available"`  // btmp 通常只有 root 可读
+	FailedAttempts uint64         `json:"failed_attempts"` // 失败登录数：启动时 btmp 中的记录加上之后新增的记录，btmp 轮转后继续累加（单调递增）
+	RecentFailures []LoginRecord  `json:"recent_failures"` // btmp 中最近的失败登录（新的在前）
+}
+
+// LoginSession 当前登录会话
+type LoginSession struct {
+	User        string `json:"user"`
+	TTY         string `json:"tty"`
+	Host        string `json:"host"` // 远程来源，本地登录为空
+	PID         int32  `json:"pid"`
+	LoginTime   string `json:"login_time"`
+	IdleSeconds int64  `json:"idle_seconds"` // 终端最后一次输入距今的秒数
+}
+
+// LoginRecord 一次登录或失败登录记录
+type LoginRecord struct {
+	User            string `json:"user"`
+	TTY             string `json:"tty"`
+	Host            string `json:"host"`
+	LoginTime       string `json:"login_time"`
+	LogoutTime      string `json:"logout_time,omitempty"`      // 仍在登录时为空
+	DurationSeconds int64  `json:"duration_seconds,omitempty"` // 已登出时的会话时长
+}
+
+// BootRecord 重启或关机记录
+type BootRecord struct {
+	Type   string `json:"type"` // reboot/shutdown
+	Time   string `json:"time"`
+	Kernel string `json:"kernel"`
+}
+
+// ComplianceMetrics 合规基线检查结果
+type ComplianceMetrics struct {
+	Enabled    bool            `json:"enabled"`
+	RulesPath  string          `json:"rules_path"`
+	LastRun    string          `json:"last_run"`
+	Score      float64         `json:"score"` // 通过率（0-100），无法检查的规则不计入
+	Rules      int             `json:"rules"`
+	Passed     int             `json:"passed"`
+	Failed     int             `json:"failed"`
+	Errors     int             `json:"errors"`
+	Results    []RuleResult    `json:"results"`
+	LoadErrors []string        `json:"load_errors"` // 无效的规则文件或规则
+	History    []ComplianceRun `json:"history"`     // 历次检查得分（新的在前）
+}
+
+// RuleResult 单条规则的检查结果
+type RuleResult struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+	Type     string `json:"type"`
+	Status   string `json:"status"` // pass/fail/error
+	Detail   string `json:"detail"`
+	Since    string `json:"since"` // 当前状态的开始时间
+}
+
+// ComplianceRun 一次检查的汇总
+type ComplianceRun struct {
+	Time   string  `json:"time"`
+	Score  float64 `json:"score"`
+	Passed int     `json:"passed"`
+	Failed int     `json:"failed"`
+	Errors int     `json:"errors"`
+}
+
+// PackageMetrics 已安装软件包清单
+type PackageMetrics struct {
+	Available     bool               `json:"available"`
+	Manager       string             `json:"manager"` // dpkg/rpm
+	Source        string             `json:"source"`  // dpkg-status/rpmdb-sqlite/rpmdb-bdb/rpm-qa
+	LastRefresh   string             `json:"last_refresh"`
+	Total         int                `json:"total"`
+	Installed     uint64             `json:"installed"` // 采集器启动以来新安装的软件包数（单调递增）
+	Upgraded      uint64             `json:"upgraded"`  // 版本变化（含降级）
+	Removed       uint64             `json:"removed"`
+	RecentChanges []PackageChange    `json:"recent_changes"` // 最近的变化（新的在前）
+	Packages      []InstalledPackage `json:"packages"`       // 按名称排序
+	Error         string             `json:"error,omitempty"`
+}
+
+// InstalledPackage 已安装的软件包
+type InstalledPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"` // dpkg 为 [epoch:]upstream-revision，rpm 为 [epoch:]version-release
+	Arch    string `json:"arch"`
+}
+
+// PackageChange 软件包变化
+type PackageChange struct {
+	Name       string `json:"name"`
+	Arch       string `json:"arch"`
+	Change     string `json:"change"` // installed/upgraded/removed
+	OldVersion string `json:"old_version,omitempty"`
+	NewVersion string `json:"new_version,omitempty"`
+	Time       string `json:"time"`
+}
+
+// IntegrityMetrics 文件完整性监控状态
+type IntegrityMetrics struct {
+	Enabled         bool         `json:"enabled"`
+	BaselineFile    string       `json:"baseline_file"`
+	BaselineCreated string       `json:"baseline_created"`
+	LastScan        string       `json:"last_scan"`
+	ScanSeconds     float64      `json:"scan_seconds"` // 最近一次扫描耗时
+	Files           int          `json:"files"`        // 基线中的条目数
+	Unreadable      int          `json:"unreadable"`   // 无权限读取、无法计算哈希的文件数
+	Truncated       bool         `json:"truncated"`    // 文件数超过上限，超出部分未监控
+	Added           uint64       `json:"added"`        // 采集器启动以来的新增文件数（单调递增）
+	Removed         uint64       `json:"removed"`
+	Modified        uint64       `json:"modified"`
+	RecentChanges   []FileChange `json:"recent_changes"`  // 最近的变化（新的在前）
+	Error           string       `json:"error,omitempty"` // 基线读写失败原因
+}
+
+// FileChange 单个文件的变化
+type FileChange struct {
+	Path   string     `json:"path"`
+	Change string     `json:"change"` // added/removed/modified
+	Time   string     `json:"time"`
+	Old    *FileState `json:"old,omitempty"`
+	New    *FileState `json:"new,omitempty"`
+}
+
+// FileState 文件在某次扫描时的状态
+type FileState struct {
+	Type   string `json:"type"`             // file/dir/symlink
+	SHA256 string `json:"sha256,omitempty"` // 仅普通文件
+	Link   string `json:"link,omitempty"`   // 符号链接目标
+	Mode   string `json:"mode"`
+	UID    uint32 `json:"uid"`
+	GID    uint32 `json:"gid"`
+	Size   int64  `json:"size"`
+	MTime  string `json:"mtime"`
+}
+
+// BruteForceMetrics 暴力破解检测状态
+type BruteForceMetrics struct {
+	Enabled       bool        `json:"enabled"`
+	Action        string      `json:"action"` // exec/ipset/nftables
+	DryRun        bool        `json:"dry_run"`
+	Threshold     int         `json:"threshold"`
+	WindowSeconds int         `json:"window_seconds"`
+	BanSeconds    int         `json:"ban_seconds"`
+	Detections    uint64      `json:"detections"` // 采集器启动以来判定为恶意来源的次数（单调递增）
+	HostileIPs    []HostileIP `json:"hostile_ips"`
+	Error         string      `json:"error,omitempty"` // 封禁表读写失败原因
+}
+
+// HostileIP 被判定为恶意的来源IP
+type HostileIP struct {
+	IP         string `json:"ip"`
+	Failures   uint64 `json:"failures"` // 判定时窗口内的失败尝试次数，包含只有 "Invalid user" 行的尝试
+	Users      int    `json:"users"`    // 判定时尝试过的不同用户名数量
+	DetectedAt string `json:"detected_at"`
+	ExpiresAt  string `json:"expires_at"`
+	Command    string `json:"command"`         // 执行（dry-run 时为将要执行）的封禁命令
+	Pending    bool   `json:"pending"`         // 封禁命令已提交，尚未执行完成
+	Blocked    bool   `json:"blocked"`         // 封禁动作已成功执行
+	Error      string `json:"error,omitempty"` // 封禁动作失败原因
+}
+
+// SSHAuthMetrics SSH 登录统计，累计值自采集器启动起单调递增
+type SSHAuthMetrics struct {
+	Failures           uint64            `json:"failures"`
+	Successes          uint64            `json:"successes"`
+	InvalidUsers       uint64            `json:"invalid_users"` // 不存在用户的尝试
+	FailuresWindow     uint64            `json:"failures_window"`
+	SuccessesWindow    uint64            `json:"successes_window"`
+	InvalidUsersWindow uint64            `json:"invalid_users_window"`
+	FailuresByMethod   map[string]uint64 `json:"failures_by_method"`  // password/publickey/keyboard-interactive...
+	SuccessesByMethod  map[string]uint64 `json:"successes_by_method"` // password/publickey/keyboard-interactive...
+}
+
+// PrivilegeMetrics sudo/su 使用统计
+type PrivilegeMetrics struct {
+	Invocations       uint64 `json:"invocations"`
+	Failures          uint64 `json:"failures"`
+	InvocationsWindow uint64 `json:"invocations_window"`
+	FailuresWindow    uint64 `json:"failures_window"`
+}
+
+// AuthSource 单个来源IP在窗口内的认证情况
+type AuthSource struct {
+	IP           string `json:"ip"`
+	Attempts     uint64 `json:"attempts"`      // 认证尝试次数：Failures 加上没有对应 "Failed" 行的不存在用户尝试
+	Failures     uint64 `json:"failures"`      // 认证失败次数，包含不存在用户的尝试
+	InvalidUsers uint64 `json:"invalid_users"` // 尝试不存在用户的次数，与 Failures 有重叠
+	Successes    uint64 `json:"successes"`
+	Users        int    `json:"users"` // 尝试过的不同用户名数量
+}
+
+// AuthTarget 单个目标用户在窗口内的认证情况
+type AuthTarget struct {
+	User      string `json:"user"`
+	Failures  uint64 `json:"failures"`
+	Successes uint64 `json:"successes"`
+	SourceIPs int    `json:"source_ips"` // 失败来源的不同IP数量
+}
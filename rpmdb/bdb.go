@@ -0,0 +1,121 @@
+package rpmdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Berkeley DB hash 数据库常量
+const (
+	bdbHashMagic     = 0x061561
+	bdbPageHeaderLen = 26
+
+	bdbPageOverflow     = 7
+	bdbPageHashUnsorted = 2
+	bdbPageHash         = 13
+
+	bdbItemKeyData = 1
+	bdbItemOffPage = 3
+)
+
+// readBDB 读取 Berkeley DB hash 格式的 Packages 文件
+// 头通常大于一页，以 H_OFFPAGE 项指向溢出页链表
+func readBDB(path string) ([]Package, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 512 {
+		return nil, errors.New("not a berkeley db file")
+	}
+
+	// 元数据页中的 magic 决定字节序
+	var order binary.ByteOrder = binary.LittleEndian
+	if binary.LittleEndian.Uint32(data[12:16]) != bdbHashMagic {
+		order = binary.BigEndian
+		if order.Uint32(data[12:16]) != bdbHashMagic {
+			return nil, errors.New("not a berkeley db hash file")
+		}
+	}
+	pageSize := int(order.Uint32(data[20:24]))
+	if pageSize < 512 || pageSize > 65536 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid berkeley db page size %d", pageSize)
+	}
+	lastPage := order.Uint32(data[32:36])
+	if int64(lastPage+1)*int64(pageSize) > int64(len(data)) {
+		lastPage = uint32(len(data)/pageSize) - 1
+	}
+
+	page := func(number uint32) []byte {
+		return data[int(number)*pageSize : int(number+1)*pageSize]
+	}
+
+	var packages []Package
+	for number := uint32(1); number <= lastPage; number++ {
+		p := page(number)
+		if p[25] != bdbPageHash && p[25] != bdbPageHashUnsorted {
+			continue
+		}
+		entries := int(order.Uint16(p[20:22]))
+		if bdbPageHeaderLen+2*entries > pageSize {
+			continue
+		}
+
+		// 键值成对存放，奇数项为值
+		for i := 1; i < entries; i += 2 {
+			offset := int(order.Uint16(p[bdbPageHeaderLen+2*i:]))
+			if offset+12 > pageSize {
+				continue
+			}
+			var blob []byte
+			switch p[offset] {
+			case bdbItemOffPage:
+				blob = readOverflow(data, pageSize, order, order.Uint32(p[offset+4:]), order.Uint32(p[offset+8:]))
+			case bdbItemKeyData:
+				// 页内数据延伸到前一项的起始位置
+				end := int(order.Uint16(p[bdbPageHeaderLen+2*(i-1):]))
+				if end > offset+1 && end <= pageSize {
+					blob = p[offset+1 : end]
+				}
+			}
+			if blob == nil {
+				continue
+			}
+			if pkg, err := parseHeader(blob); err == nil {
+				packages = append(packages, pkg)
+			}
+		}
+	}
+	return packages, nil
+}
+
+// readOverflow 沿溢出页链表读取 length 字节
+// 溢出页头中 hf_offset（即空闲区偏移字段）记录本页的数据长度
+func readOverflow(data []byte, pageSize int, order binary.ByteOrder, number, length uint32) []byte {
+	if int64(length) > int64(len(data)) {
+		return nil
+	}
+	blob := make([]byte, 0, length)
+	for visited := 0; number != 0 && uint32(len(blob)) < length; visited++ {
+		start := int64(number) * int64(pageSize)
+		if start+int64(pageSize) > int64(len(data)) || visited > len(data)/pageSize {
+			return nil
+		}
+		p := data[start : start+int64(pageSize)]
+		if p[25] != bdbPageOverflow {
+			return nil
+		}
+		used := int(order.Uint16(p[22:24]))
+		if bdbPageHeaderLen+used > pageSize {
+			return nil
+		}
+		blob = append(blob, p[bdbPageHeaderLen:bdbPageHeaderLen+used]...)
+		number = order.Uint32(p[16:20])
+	}
+	if uint32(len(blob)) < length {
+		return nil
+	}
+	return blob[:length]
+}
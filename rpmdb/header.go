@@ -0,0 +1,92 @@
+// Package rpmdb 不依赖 rpm 命令与 cgo，直接读取 rpm 数据库（sqlite 与 Berkeley DB hash 格式）中的软件包头。
+package rpmdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// Package 已安装的软件包
+type Package struct {
+	Name    string
+	Version string // [epoch:]version-release
+	Arch    string
+}
+
+// rpm 头中使用的标签
+const (
+	tagName    = 1000
+	tagVersion = 1001
+	tagRelease = 1002
+	tagEpoch   = 1003
+	tagArch    = 1022
+)
+
+// rpm 头数据类型
+const (
+	typeInt32  = 4
+	typeString = 6
+)
+
+// errInvalidHeader 头格式错误
+var errInvalidHeader = errors.New("invalid rpm header")
+
+// parseHeader 解析数据库中保存的头（不含 magic 的 header blob）
+//
+//	il uint32 | dl uint32 | il 个 {tag, type, offset, count} | dl 字节数据区
+func parseHeader(blob []byte) (Package, error) {
+	if len(blob) < 8 {
+		return Package{}, errInvalidHeader
+	}
+	il := int(binary.BigEndian.Uint32(blob[0:4]))
+	dl := int(binary.BigEndian.Uint32(blob[4:8]))
+	start := 8 + il*16
+	if il <= 0 || il > 0xffff || dl < 0 || start+dl > len(blob) {
+		return Package{}, errInvalidHeader
+	}
+	data := blob[start : start+dl]
+
+	var pkg Package
+	var version, release, epoch string
+	for i := 0; i < il; i++ {
+		entry := blob[8+i*16 : 8+(i+1)*16]
+		tag := binary.BigEndian.Uint32(entry[0:4])
+		typ := binary.BigEndian.Uint32(entry[4:8])
+		offset := int(int32(binary.BigEndian.Uint32(entry[8:12])))
+		if offset < 0 || offset >= len(data) {
+			continue
+		}
+
+		switch {
+		case typ == typeString && tag == tagName:
+			pkg.Name = cString(data[offset:])
+		case typ == typeString && tag == tagVersion:
+			version = cString(data[offset:])
+		case typ == typeString && tag == tagRelease:
+			release = cString(data[offset:])
+		case typ == typeString && tag == tagArch:
+			pkg.Arch = cString(data[offset:])
+		case typ == typeInt32 && tag == tagEpoch && offset+4 <= len(data):
+			epoch = strconv.FormatUint(uint64(binary.BigEndian.Uint32(data[offset:offset+4])), 10)
+		}
+	}
+
+	if pkg.Name == "" {
+		return Package{}, errInvalidHeader
+	}
+	pkg.Version = version + "-" + release
+	if epoch != "" {
+		pkg.Version = epoch + ":" + pkg.Version
+	}
+	return pkg, nil
+}
+
+// cString 截取以 NUL 结尾的字符串
+func cString(data []byte) string {
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return string(data[:i])
+	}
+	return string(data)
+}
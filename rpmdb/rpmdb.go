@@ -0,0 +1,62 @@
+package rpmdb
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// 数据库格式
+const (
+	FormatSQLite = "sqlite" // rpm 4.16 及以后（RHEL 9、Fedora 33+）
+	FormatBDB    = "bdb"    // Berkeley DB hash（RHEL 8 及以前）
+	FormatNDB    = "ndb"    // SUSE 使用的 rpm 原生格式，暂不支持直接读取
+)
+
+// ErrUnsupported 数据库格式无法直接读取，调用方应退回到 rpm -qa
+var ErrUnsupported = errors.New("unsupported rpm database format")
+
+// Files 返回数据库目录下存在的数据库文件及其格式
+// 目录中同时存在多种格式时（如升级后遗留旧文件）按 sqlite、ndb、bdb 顺序取第一个
+func Files(dir string) (path, format string) {
+	for _, candidate := range []struct{ name, format string }{
+		{"rpmdb.sqlite", FormatSQLite},
+		{"Packages.db", FormatNDB},
+		{"Packages", FormatBDB},
+	} {
+		path := filepath.Join(dir, candidate.name)
+		if _, err := os.Stat(path); err == nil {
+			return path, candidate.format
+		}
+	}
+	return "", ""
+}
+
+// Read 读取数据库中的全部软件包（不含 gpg-pubkey 伪包）
+// sqlite 数据库存在未检查点的 WAL 时主文件内容可能过期，返回 ErrUnsupported
+func Read(path, format string) ([]Package, error) {
+	var packages []Package
+	var err error
+	switch format {
+	case FormatSQLite:
+		if info, statErr := os.Stat(path + "-wal"); statErr == nil && info.Size() > 0 {
+			return nil, ErrUnsupported
+		}
+		packages, err = readSQLite(path)
+	case FormatBDB:
+		packages, err = readBDB(path)
+	default:
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := packages[:0]
+	for _, pkg := range packages {
+		if pkg.Name != "gpg-pubkey" {
+			result = append(result, pkg)
+		}
+	}
+	return result, nil
+}
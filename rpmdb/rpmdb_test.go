@@ -0,0 +1,117 @@
+package rpmdb
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// expectedPackages 读取 testdata/rpm-qa.txt（rpm -qa 按 "名称 [epoch:]版本-发行 架构" 输出），去掉 gpg-pubkey
+func expectedPackages(t *testing.T) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "rpm-qa.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if !strings.HasPrefix(line, "gpg-pubkey ") {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// formatPackages 按 rpm-qa.txt 的格式输出并排序
+func formatPackages(packages []Package) []string {
+	lines := make([]string, 0, len(packages))
+	for _, pkg := range packages {
+		arch := pkg.Arch
+		if arch == "" {
+			arch = "(none)"
+		}
+		lines = append(lines, pkg.Name+" "+pkg.Version+" "+arch)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestRead(t *testing.T) {
+	want := expectedPackages(t)
+	for _, format := range []string{FormatSQLite, FormatBDB} {
+		t.Run(format, func(t *testing.T) {
+			name := map[string]string{FormatSQLite: "rpmdb.sqlite", FormatBDB: "Packages"}[format]
+			packages, err := Read(filepath.Join("testdata", name), format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := formatPackages(packages); !reflect.DeepEqual(got, want) {
+				t.Errorf("Read returned %d packages, want %d\ngot:  %v\nwant: %v", len(got), len(want), got, want)
+			}
+		})
+	}
+}
+
+func TestReadSQLiteWithWAL(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("testdata", "rpmdb.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "rpmdb.sqlite")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// 未检查点的 WAL 中可能有主文件之外的更新
+	if err := os.WriteFile(path+"-wal", []byte("wal"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path, FormatSQLite); err != ErrUnsupported {
+		t.Errorf("Read with WAL: err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	if path, format := Files(dir); path != "" || format != "" {
+		t.Errorf("empty dir: %q %q", path, format)
+	}
+
+	for _, name := range []string{"Packages", "Packages.db", "rpmdb.sqlite"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 升级后遗留旧格式时优先使用 sqlite
+	if path, format := Files(dir); path != filepath.Join(dir, "rpmdb.sqlite") || format != FormatSQLite {
+		t.Errorf("Files = %q %q", path, format)
+	}
+}
+
+func TestReadRejectsGarbage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "garbage")
+	if err := os.WriteFile(path, []byte(strings.Repeat("not a database ", 400)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{FormatSQLite, FormatBDB} {
+		if _, err := Read(path, format); err == nil {
+			t.Errorf("%s: expected error for garbage input", format)
+		}
+	}
+	if _, err := Read(path, FormatNDB); err != ErrUnsupported {
+		t.Errorf("ndb: err = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	if _, err := parseHeader([]byte{0, 0, 0, 1}); err == nil {
+		t.Error("expected error for truncated header")
+	}
+	// il 与 dl 超出 blob 长度
+	if _, err := parseHeader([]byte{0, 0, 0, 5, 0, 0, 1, 0}); err == nil {
+		t.Error("expected error for oversized header")
+	}
+}
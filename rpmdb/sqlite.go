@@ -0,0 +1,316 @@
+package rpmdb
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// sqliteMagic sqlite 数据库文件头
+const sqliteMagic = "SQLite format 3\x00"
+
+// b-tree 页类型
+const (
+	sqliteInteriorTable = 5
+	sqliteLeafTable     = 13
+)
+
+// sqliteMaxDepth b-tree 最大深度，防止损坏的文件导致死循环
+const sqliteMaxDepth = 32
+
+// sqliteFile 只读的最小 sqlite 解析器，只支持遍历表 b-tree
+type sqliteFile struct {
+	data     []byte
+	pageSize int
+	usable   int // 页大小减去每页保留字节
+}
+
+// readSQLite 读取 rpmdb.sqlite 的 Packages 表（hnum INTEGER PRIMARY KEY, blob BLOB）
+func readSQLite(path string) ([]Package, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := openSQLite(data)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := db.tableRoot("Packages")
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []Package
+	err = db.walk(root, 0, func(record []byte) {
+		// hnum 是 rowid 的别名，记录中该列为 NULL，第二列为头
+		columns, err := parseRecord(record)
+		if err != nil || len(columns) < 2 {
+			return
+		}
+		if blob, ok := columns[1].([]byte); ok {
+			if pkg, err := parseHeader(blob); err == nil {
+				packages = append(packages, pkg)
+			}
+		}
+	})
+	return packages, err
+}
+
+// openSQLite 校验文件头并读取页大小
+func openSQLite(data []byte) (*sqliteFile, error) {
+	if len(data) < 100 || string(data[:16]) != sqliteMagic {
+		return nil, errors.New("not a sqlite database")
+	}
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("invalid sqlite page size %d", pageSize)
+	}
+	return &sqliteFile{
+		data:     data,
+		pageSize: pageSize,
+		usable:   pageSize - int(data[20]),
+	}, nil
+}
+
+// page 返回页内容（页号从 1 开始）
+func (db *sqliteFile) page(number uint32) ([]byte, error) {
+	start := int64(number-1) * int64(db.pageSize)
+	if number == 0 || start+int64(db.pageSize) > int64(len(db.data)) {
+		return nil, fmt.Errorf("sqlite page %d out of range", number)
+	}
+	return db.data[start : start+int64(db.pageSize)], nil
+}
+
+// tableRoot 从 sqlite_master（根页为 1）查找表的根页
+func (db *sqliteFile) tableRoot(name string) (uint32, error) {
+	var root uint32
+	err := db.walk(1, 0, func(record []byte) {
+		// type, name, tbl_name, rootpage, sql
+		columns, err := parseRecord(record)
+		if err != nil || len(columns) < 4 {
+			return
+		}
+		typ, _ := columns[0].(string)
+		table, _ := columns[1].(string)
+		page, _ := columns[3].(int64)
+		if typ == "table" && table == name && page > 0 {
+			root = uint32(page)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+	if root == 0 {
+		return 0, fmt.Errorf("table %s not found", name)
+	}
+	return root, nil
+}
+
+// walk 按 rowid 顺序遍历表 b-tree，对每行的记录调用 fn
+func (db *sqliteFile) walk(number uint32, depth int, fn func(record []byte)) error {
+	if depth > sqliteMaxDepth {
+		return errors.New("sqlite b-tree too deep")
+	}
+	page, err := db.page(number)
+	if err != nil {
+		return err
+	}
+
+	// 第 1 页前 100 字节为文件头
+	offset := 0
+	if number == 1 {
+		offset = 100
+	}
+	if offset+12 > len(page) {
+		return errors.New("truncated sqlite page")
+	}
+	header := page[offset:]
+	cells := int(binary.BigEndian.Uint16(header[3:5]))
+
+	switch header[0] {
+	case sqliteInteriorTable:
+		pointers := header[12:]
+		if 2*cells > len(pointers) {
+			return errors.New("truncated sqlite page")
+		}
+		for i := 0; i < cells; i++ {
+			cell := int(binary.BigEndian.Uint16(pointers[2*i:]))
+			if cell+4 > len(page) {
+				return errors.New("invalid sqlite cell pointer")
+			}
+			if err := db.walk(binary.BigEndian.Uint32(page[cell:]), depth+1, fn); err != nil {
+				return err
+			}
+		}
+		return db.walk(binary.BigEndian.Uint32(header[8:12]), depth+1, fn)
+	case sqliteLeafTable:
+		pointers := header[8:]
+		if 2*cells > len(pointers) {
+			return errors.New("truncated sqlite page")
+		}
+		for i := 0; i < cells; i++ {
+			cell := int(binary.BigEndian.Uint16(pointers[2*i:]))
+			if cell >= len(page) {
+				return errors.New("invalid sqlite cell pointer")
+			}
+			record, err := db.payload(page[cell:])
+			if err != nil {
+				return err
+			}
+			fn(record)
+		}
+		return nil
+	}
+	return fmt.Errorf("unexpected sqlite page type %d", header[0])
+}
+
+// payload 读取叶子单元的完整内容，超出页内容量的部分在溢出页链表中
+func (db *sqliteFile) payload(cell []byte) ([]byte, error) {
+	size, n := varint(cell)
+	if n == 0 {
+		return nil, errors.New("invalid sqlite cell")
+	}
+	_, m := varint(cell[n:]) // rowid
+	if m == 0 {
+		return nil, errors.New("invalid sqlite cell")
+	}
+	cell = cell[n+m:]
+
+	total := int(size)
+	if size > uint64(len(db.data)) {
+		return nil, errors.New("invalid sqlite payload size")
+	}
+	local := db.localSize(total)
+	if local > len(cell) {
+		return nil, errors.New("truncated sqlite cell")
+	}
+	if local == total {
+		return cell[:total], nil
+	}
+	if local+4 > len(cell) {
+		return nil, errors.New("truncated sqlite cell")
+	}
+
+	payload := make([]byte, 0, total)
+	payload = append(payload, cell[:local]...)
+	next := binary.BigEndian.Uint32(cell[local:])
+	for next != 0 && len(payload) < total {
+		page, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		chunk := page[4:db.usable]
+		if remain := total - len(payload); len(chunk) > remain {
+			chunk = chunk[:remain]
+		}
+		payload = append(payload, chunk...)
+		next = binary.BigEndian.Uint32(page[:4])
+	}
+	if len(payload) < total {
+		return nil, errors.New("truncated sqlite overflow chain")
+	}
+	return payload, nil
+}
+
+// localSize 表叶子单元保存在页内的字节数（sqlite 文件格式 1.6 节）
+func (db *sqliteFile) localSize(total int) int {
+	maxLocal := db.usable - 35
+	if total <= maxLocal {
+		return total
+	}
+	minLocal := (db.usable-12)*32/255 - 23
+	local := minLocal + (total-minLocal)%(db.usable-4)
+	if local > maxLocal {
+		return minLocal
+	}
+	return local
+}
+
+// parseRecord 解析记录，列值为 nil、int64、float64（仅占位）、string 或 []byte
+func parseRecord(record []byte) ([]interface{}, error) {
+	headerSize, n := varint(record)
+	if n == 0 || headerSize > uint64(len(record)) {
+		return nil, errors.New("invalid sqlite record")
+	}
+
+	var types []uint64
+	for pos := n; pos < int(headerSize); {
+		typ, m := varint(record[pos:int(headerSize)])
+		if m == 0 {
+			return nil, errors.New("invalid sqlite record")
+		}
+		types = append(types, typ)
+		pos += m
+	}
+
+	columns := make([]interface{}, 0, len(types))
+	body := record[headerSize:]
+	for _, typ := range types {
+		size := serialSize(typ)
+		if size > len(body) {
+			return nil, errors.New("truncated sqlite record")
+		}
+		value := body[:size]
+		body = body[size:]
+
+		switch {
+		case typ == 0:
+			columns = append(columns, nil)
+		case typ >= 1 && typ <= 6:
+			// 大端有符号整数
+			v := int64(int8(value[0]))
+			for _, b := range value[1:] {
+				v = v<<8 | int64(b)
+			}
+			columns = append(columns, v)
+		case typ == 7:
+			columns = append(columns, float64(0))
+		case typ == 8:
+			columns = append(columns, int64(0))
+		case typ == 9:
+			columns = append(columns, int64(1))
+		case typ >= 12 && typ%2 == 0:
+			columns = append(columns, value)
+		case typ >= 13:
+			columns = append(columns, string(value))
+		default:
+			return nil, fmt.Errorf("unsupported sqlite serial type %d", typ)
+		}
+	}
+	return columns, nil
+}
+
+// serialSize 列序列类型对应的字节数
+func serialSize(typ uint64) int {
+	switch {
+	case typ <= 4:
+		return int(typ)
+	case typ == 5:
+		return 6
+	case typ == 6, typ == 7:
+		return 8
+	case typ >= 12:
+		return int((typ - 12) / 2)
+	}
+	return 0
+}
+
+// varint sqlite 大端变长整数（最长 9 字节），返回值与占用字节数，无效时字节数为 0
+func varint(data []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9 && i < len(data); i++ {
+		if i == 8 {
+			return v<<8 | uint64(data[i]), 9
+		}
+		v = v<<7 | uint64(data[i]&0x7f)
+		if data[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
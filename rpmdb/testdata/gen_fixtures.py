@@ -0,0 +1,162 @@
+#!/usr/bin/env python3
+"""生成 rpmdb 测试数据。
+
+hello-1-1 的头取自真实的 rpm 软件包（hello.rpm），其余软件包的头按 rpm 头格式构造。
+rpmdb.sqlite 由 SQLite 库按 rpm 4.16 的表结构写入，Packages 通过 ctypes 调用
+libdb-5.3 以 DB_HASH 格式写入，两者的页面布局均由各自的数据库库生成。
+
+用法: python3 gen_fixtures.py /path/to/hello.rpm
+"""
+import ctypes
+import os
+import sqlite3
+import struct
+import sys
+
+HERE = os.path.dirname(os.path.abspath(__file__))
+
+TAG_NAME, TAG_VERSION, TAG_RELEASE, TAG_EPOCH, TAG_SUMMARY, TAG_DESCRIPTION, TAG_ARCH = \
+    1000, 1001, 1002, 1003, 1004, 1005, 1022
+TAG_BASENAMES = 1117
+TYPE_INT32, TYPE_STRING, TYPE_STRING_ARRAY, TYPE_I18NSTRING = 4, 6, 8, 9
+
+
+def rpm_main_header(path):
+    """从 .rpm 文件中取出主头（去掉 8 字节 magic），即数据库中保存的 blob。"""
+    data = open(path, "rb").read()
+    offset = 96  # lead
+    for section in range(2):
+        assert data[offset:offset + 3] == b"\x8e\xad\xe8"
+        il, dl = struct.unpack(">II", data[offset + 8:offset + 16])
+        end = offset + 16 + il * 16 + dl
+        if section == 1:
+            return data[offset + 8:end]
+        offset = (end + 7) // 8 * 8  # 签名头按 8 字节对齐
+
+
+def build_header(entries):
+    """entries: [(tag, type, value)]，按 rpm 头格式编码。"""
+    index, store = [], b""
+    for tag, typ, value in entries:
+        if typ == TYPE_INT32:
+            store += b"\0" * (-len(store) % 4)
+            offset, count = len(store), 1
+            store += struct.pack(">I", value)
+        elif typ == TYPE_STRING_ARRAY:
+            offset, count = len(store), len(value)
+            store += b"".join(v.encode() + b"\0" for v in value)
+        else:
+            offset, count = len(store), 1
+            store += value.encode() + b"\0"
+        index.append(struct.pack(">IIII", tag, typ, offset, count))
+    return struct.pack(">II", len(index), len(store)) + b"".join(index) + store
+
+
+def package(name, version, release, arch, epoch=None, files=0):
+    entries = [
+        (TAG_NAME, TYPE_STRING, name),
+        (TAG_VERSION, TYPE_STRING, version),
+        (TAG_RELEASE, TYPE_STRING, release),
+        (TAG_SUMMARY, TYPE_I18NSTRING, name + " package"),
+    ]
+    if epoch is not None:
+        entries.append((TAG_EPOCH, TYPE_INT32, epoch))
+    if arch is not None:
+        entries.append((TAG_ARCH, TYPE_STRING, arch))
+    if files:
+        entries.append((TAG_BASENAMES, TYPE_STRING_ARRAY,
+                        ["locale-file-%05d.mo" % i for i in range(files)]))
+    return build_header(entries)
+
+
+def packages(hello_rpm):
+    pkgs = [
+        rpm_main_header(hello_rpm),
+        package("bash", "5.1.8", "6.el9_1", "x86_64"),
+        package("openssl-libs", "3.0.7", "24.el9", "x86_64", epoch=1),
+        package("kernel-core", "5.14.0", "362.8.1.el9_3", "x86_64"),
+        package("kernel-core", "5.14.0", "362.13.1.el9_3", "x86_64"),
+        package("glibc", "2.34", "83.el9_3.7", "i686"),
+        package("glibc", "2.34", "83.el9_3.7", "x86_64"),
+        # 头大于页大小，sqlite 与 Berkeley DB 均使用溢出页保存
+        package("glibc-all-langpacks", "2.34", "83.el9_3.7", "x86_64", files=1500),
+        package("gpg-pubkey", "fd431d51", "4ae0493b", None),
+    ]
+    for i in range(60):
+        pkg_release = "%d.el9" % (i % 7 + 1)
+        pkgs.append(package("filler-%02d" % i, "1.%d" % i, pkg_release, "noarch"))
+    return pkgs
+
+
+def write_sqlite(path, blobs):
+    if os.path.exists(path):
+        os.remove(path)
+    db = sqlite3.connect(path)
+    db.execute("CREATE TABLE IF NOT EXISTS 'Packages' (hnum INTEGER PRIMARY KEY AUTOINCREMENT,blob BLOB NOT NULL)")
+    db.execute("CREATE TABLE IF NOT EXISTS 'Name' (key '' TEXT NOT NULL, hnum INTEGER NOT NULL, idx INTEGER NOT NULL, "
+               "FOREIGN KEY (hnum) REFERENCES 'Packages'(hnum))")
+    for blob in blobs:
+        cur = db.execute("INSERT INTO 'Packages' (blob) VALUES (?)", (blob,))
+        name = blob[8 + struct.unpack(">I", blob[:4])[0] * 16:].split(b"\0")[0].decode()
+        db.execute("INSERT INTO 'Name' VALUES (?, ?, 0)", (name, cur.lastrowid))
+    db.commit()
+    db.close()
+
+
+class DBT(ctypes.Structure):
+    _fields_ = [("data", ctypes.c_void_p), ("size", ctypes.c_uint32), ("ulen", ctypes.c_uint32),
+                ("dlen", ctypes.c_uint32), ("doff", ctypes.c_uint32), ("app_data", ctypes.c_void_p),
+                ("flags", ctypes.c_uint32)]
+
+
+def write_bdb(path, blobs):
+    if os.path.exists(path):
+        os.remove(path)
+    lib = ctypes.CDLL("libdb-5.3.so")
+    db = ctypes.c_void_p()
+    assert lib.db_create(ctypes.byref(db), None, 0) == 0
+    assert lib.__db_set_pagesize(db, 4096) == 0
+    DB_HASH, DB_CREATE = 2, 1
+    assert lib.__db_open_pp(db, None, path.encode(), None, DB_HASH, DB_CREATE, 0o644) == 0
+
+    def put(key, value):
+        k = ctypes.create_string_buffer(key, len(key))
+        v = ctypes.create_string_buffer(value, len(value))
+        kd = DBT(ctypes.cast(k, ctypes.c_void_p), len(key), 0, 0, 0, None, 0)
+        vd = DBT(ctypes.cast(v, ctypes.c_void_p), len(value), 0, 0, 0, None, 0)
+        assert lib.__db_put_pp(db, None, ctypes.byref(kd), ctypes.byref(vd), 0) == 0
+
+    # rpm 以主机字节序的头编号为键，0 号记录保存最大编号
+    put(struct.pack("<I", 0), struct.pack("<I", len(blobs)))
+    for i, blob in enumerate(blobs, 1):
+        put(struct.pack("<I", i), blob)
+    assert lib.__db_close_pp(db, 0) == 0
+
+
+def query_format(blob):
+    """按 rpm -qa --queryformat '%{NAME} %|EPOCH?{%{EPOCH}:}:{}|%{VERSION}-%{RELEASE} %{ARCH}\\n' 输出一行。"""
+    il, dl = struct.unpack(">II", blob[:8])
+    store = blob[8 + il * 16:8 + il * 16 + dl]
+    tags = {}
+    for i in range(il):
+        tag, typ, offset, _ = struct.unpack(">IIII", blob[8 + i * 16:24 + i * 16])
+        if typ == TYPE_INT32:
+            tags[tag] = str(struct.unpack(">I", store[offset:offset + 4])[0])
+        elif typ == TYPE_STRING:
+            tags[tag] = store[offset:].split(b"\0")[0].decode()
+    epoch = tags[TAG_EPOCH] + ":" if TAG_EPOCH in tags else ""
+    return "%s %s%s-%s %s" % (tags[TAG_NAME], epoch, tags[TAG_VERSION], tags[TAG_RELEASE],
+                              tags.get(TAG_ARCH, "(none)"))
+
+
+def main():
+    blobs = packages(sys.argv[1])
+    write_sqlite(os.path.join(HERE, "rpmdb.sqlite"), blobs)
+    write_bdb(os.path.join(HERE, "Packages"), blobs)
+    with open(os.path.join(HERE, "rpm-qa.txt"), "w") as f:
+        for line in sorted(query_format(blob) for blob in blobs):
+            f.write(line + "\n")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,16 @@
+//go:build !windows
+
+package tailer
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileInode 获取文件 inode，用于识别 rename 轮转
+func fileInode(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Ino)
+	}
+	return 0
+}
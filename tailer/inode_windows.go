@@ -0,0 +1,10 @@
+//go:build windows
+
+package tailer
+
+import "os"
+
+// fileInode Windows 没有 inode，轮转只能通过指纹识别
+func fileInode(info os.FileInfo) uint64 {
+	return 0
+}
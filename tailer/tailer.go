@@ -0,0 +1,359 @@
+// Package tailer 增量读取文本日志，跨采集周期与进程重启保持读取位置，
+// 并处理 rename 与 copytruncate 两种日志轮转方式。
+package tailer
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fingerprintSize 用于识别文件的开头字节数
+// 轮转压缩后的 .gz 文件 inode 已变化，只能靠内容指纹匹配
+const fingerprintSize = 1024
+
+// Position 读取位置，持久化到状态文件
+type Position struct {
+	Inode          uint64 `json:"inode"`
+	Offset         int64  `json:"offset"`          // 已处理的完整行末尾偏移
+	Fingerprint    string `json:"fingerprint"`     // 文件开头 FingerprintLen 字节的 SHA-256
+	FingerprintLen int    `json:"fingerprint_len"` // 计算指纹时文件开头的字节数
+}
+
+// Options Tailer 选项
+type Options struct {
+	StateFile string // 读取位置持久化文件，为空不持久化
+	FromStart bool   // 没有历史位置时从文件开头读取，默认从末尾开始
+}
+
+// PersistError 读取位置写入状态文件失败
+// 本次读取已成功，Tailer 仍保持打开，调用方可以继续使用
+type PersistError struct {
+	Err error
+}
+
+func (e *PersistError) Error() string {
+	return "tailer: save state: " + e.Err.Error()
+}
+
+func (e *PersistError) Unwrap() error {
+	return e.Err
+}
+
+// Tailer 单个日志文件的增量读取器，非并发安全
+type Tailer struct {
+	path    string
+	opts    Options
+	file    *os.File
+	pos     Position
+	partial []byte // 尚未以换行结尾的残留内容
+	loaded  bool
+}
+
+// StateFileFor 返回日志文件在状态目录中的位置文件路径
+// 如 /var/log/auth.log -> <dir>/tail-var_log_auth.log.json
+func StateFileFor(dir, logPath string) string {
+	name := strings.ReplaceAll(strings.Trim(logPath, "/"), "/", "_")
+	return filepath.Join(dir, "tail-"+name+".json")
+}
+
+// New 创建 Tailer，首次 ReadLines 时才打开文件
+func New(path string, opts Options) *Tailer {
+	return &Tailer{path: path, opts: opts}
+}
+
+// Path 返回跟踪的日志路径
+func (t *Tailer) Path() string {
+	return t.path
+}
+
+// ReadLines 读取自上次调用以来新增的完整行
+// 检测到轮转时先读完旧文件剩余内容，再从新文件开头读取
+// 只有读取位置持久化失败时返回 *PersistError，此时文件句柄保持打开
+func (t *Tailer) ReadLines(fn func(line string)) error {
+	if !t.loaded {
+		t.loadState()
+		t.loaded = true
+	}
+
+	if t.file == nil {
+		if err := t.open(fn, false); err != nil {
+			return err
+		}
+	} else if err := t.checkRotation(fn); err != nil {
+		return err
+	}
+
+	if err := t.readAvailable(t.file, false, fn); err != nil {
+		return err
+	}
+
+	t.updateFingerprint()
+	if err := t.saveState(); err != nil {
+		return &PersistError{Err: err}
+	}
+	return nil
+}
+
+// Close 关闭当前文件句柄
+func (t *Tailer) Close() error {
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
+
+// open 打开文件并根据历史位置定位
+// rotated 为 true 表示运行中检测到轮转，新文件总是从开头读取
+func (t *Tailer) open(fn func(line string), rotated bool) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	var offset int64
+	switch {
+	case t.pos.Inode == 0 && t.pos.FingerprintLen == 0:
+		// 没有历史位置
+		if !t.opts.FromStart && !rotated {
+			offset = info.Size()
+		}
+	case fileInode(info) == t.pos.Inode && t.matchesFingerprint(file) && info.Size() >= t.pos.Offset:
+		offset = t.pos.Offset
+	case fileInode(info) == t.pos.Inode:
+		// 停止期间被 copytruncate 截断（文件变短，或重新写入后开头内容已不同）：
+		// 旧内容在 inode 不同的副本中，按指纹查找并读完，再从开头读取
+		t.drainRotated(fn, true)
+	default:
+		// 停止期间发生了 rename 轮转：先读完旧文件中未处理的部分
+		t.drainRotated(fn, false)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	t.file = file
+	t.partial = nil
+	t.pos = Position{Inode: fileInode(info), Offset: offset}
+	return nil
+}
+
+// checkRotation 检查路径是否已指向新文件或文件是否被截断
+func (t *Tailer) checkRotation(fn func(line string)) error {
+	current, err := t.file.Stat()
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		// 已被改名但新文件尚未创建，继续读旧句柄
+		return nil
+	}
+
+	if !os.SameFile(info, current) {
+		// rename 轮转：读完旧句柄后切换到新文件
+		if err := t.readAvailable(t.file, true, fn); err != nil {
+			return err
+		}
+		t.file.Close()
+		t.file = nil
+		t.pos = Position{}
+		return t.open(fn, true)
+	}
+
+	if info.Size() < t.pos.Offset+int64(len(t.partial)) || !t.matchesFingerprint(t.file) {
+		// copytruncate 轮转（截断后可能已重新写入超过原偏移，用指纹识别）
+		// 复制与截断之间写入的内容只在副本中，能找到副本时从原偏移读完
+		t.partial = nil
+		t.drainRotated(fn, true)
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		t.pos.Offset = 0
+		t.pos.Fingerprint = ""
+		t.pos.FingerprintLen = 0
+	}
+
+	return nil
+}
+
+// drainRotated 在轮转后的文件中查找历史位置所在的文件并读完剩余内容
+// 依次检查 path.0、path.1、path-<日期> 以及对应的 .gz 文件
+// copied 为 true 表示 copytruncate 产生的副本，inode 与原文件不同，只按指纹匹配
+func (t *Tailer) drainRotated(fn func(line string), copied bool) {
+	candidates := []string{t.path + ".0", t.path + ".1"}
+	if dated, err := filepath.Glob(t.path + "-*"); err == nil {
+		sort.Sort(sort.Reverse(sort.StringSlice(dated)))
+		candidates = append(candidates, dated...)
+	}
+	candidates = append(candidates, t.path+".0.gz", t.path+".1.gz", t.path+".2.gz")
+
+	for _, candidate := range candidates {
+		if t.drainCandidate(candidate, copied, fn) {
+			return
+		}
+	}
+}
+
+// drainCandidate 若候选文件即为历史位置所在的文件，则从历史偏移读到末尾
+func (t *Tailer) drainCandidate(path string, copied bool, fn func(line string)) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	if strings.HasSuffix(path, ".gz") {
+		if t.pos.FingerprintLen == 0 {
+			return false
+		}
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return false
+		}
+		defer gz.Close()
+
+		head := make([]byte, t.pos.FingerprintLen)
+		if _, err := io.ReadFull(gz, head); err != nil || fingerprint(head) != t.pos.Fingerprint {
+			return false
+		}
+		if _, err := io.CopyN(io.Discard, gz, t.pos.Offset-int64(len(head))); err != nil {
+			return false
+		}
+		t.readAvailable(gz, true, fn)
+		return true
+	}
+
+	info, err := file.Stat()
+	if err != nil || !t.matchesFingerprint(file) {
+		return false
+	}
+	if copied {
+		// 没有指纹时无法确认副本来源
+		if t.pos.FingerprintLen == 0 || info.Size() < t.pos.Offset {
+			return false
+		}
+	} else if fileInode(info) != t.pos.Inode {
+		return false
+	}
+	if _, err := file.Seek(t.pos.Offset, io.SeekStart); err != nil {
+		return false
+	}
+	t.readAvailable(file, true, fn)
+	return true
+}
+
+// readAvailable 读取到当前末尾，回调每个完整行
+// final 为 true 表示文件不会再增长，末尾不完整的行也一并输出
+func (t *Tailer) readAvailable(r io.Reader, final bool, fn func(line string)) error {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		chunk, err := reader.ReadBytes('\n')
+		if len(chunk) > 0 {
+			t.partial = append(t.partial, chunk...)
+			if t.partial[len(t.partial)-1] == '\n' {
+				t.pos.Offset += int64(len(t.partial))
+				fn(strings.TrimRight(string(t.partial), "\r\n"))
+				t.partial = t.partial[:0]
+			}
+		}
+		if err == io.EOF {
+			if final && len(t.partial) > 0 {
+				t.pos.Offset += int64(len(t.partial))
+				fn(string(t.partial))
+				t.partial = t.partial[:0]
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// matchesFingerprint 检查文件开头是否与记录的指纹一致
+// 没有记录指纹时视为一致
+func (t *Tailer) matchesFingerprint(file *os.File) bool {
+	if t.pos.FingerprintLen == 0 {
+		return true
+	}
+	head := make([]byte, t.pos.FingerprintLen)
+	if _, err := file.ReadAt(head, 0); err != nil {
+		return false
+	}
+	return fingerprint(head) == t.pos.Fingerprint
+}
+
+// updateFingerprint 文件开头不足 fingerprintSize 时随文件增长更新指纹
+func (t *Tailer) updateFingerprint() {
+	if t.file == nil || t.pos.FingerprintLen >= fingerprintSize {
+		return
+	}
+	head := make([]byte, fingerprintSize)
+	n, _ := t.file.ReadAt(head, 0)
+	if int64(n) > t.pos.Offset {
+		// 只对已处理的内容计算指纹，保证与之后的偏移一致
+		n = int(t.pos.Offset)
+	}
+	if n > t.pos.FingerprintLen {
+		t.pos.Fingerprint = fingerprint(head[:n])
+		t.pos.FingerprintLen = n
+	}
+}
+
+// loadState 从状态文件恢复读取位置
+func (t *Tailer) loadState() {
+	if t.opts.StateFile == "" {
+		return
+	}
+	data, err := os.ReadFile(t.opts.StateFile)
+	if err != nil {
+		return
+	}
+	var pos Position
+	if err := json.Unmarshal(data, &pos); err == nil {
+		t.pos = pos
+	}
+}
+
+// saveState 将读取位置写入状态文件（先写临时文件再改名）
+func (t *Tailer) saveState() error {
+	if t.opts.StateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(t.pos)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.opts.StateFile), 0755); err != nil {
+		return err
+	}
+	tmp := t.opts.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.opts.StateFile)
+}
+
+// fingerprint 计算内容指纹
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
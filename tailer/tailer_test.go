@@ -0,0 +1,298 @@
+package tailer
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// logFile 测试用的日志文件
+type logFile struct {
+	t    *testing.T
+	path string
+}
+
+func newLogFile(t *testing.T) *logFile {
+	t.Helper()
+	return &logFile{t: t, path: filepath.Join(t.TempDir(), "auth.log")}
+}
+
+// write 覆盖写入（新建文件）
+func (f *logFile) write(content string) {
+	f.t.Helper()
+	if err := os.WriteFile(f.path, []byte(content), 0644); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// append 追加写入，保持 inode 不变
+func (f *logFile) append(content string) {
+	f.t.Helper()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(content); err != nil {
+		f.t.Fatal(err)
+	}
+}
+
+// rename 模拟 logrotate 默认方式：改名后创建新文件
+func (f *logFile) rename(suffix, newContent string) {
+	f.t.Helper()
+	if err := os.Rename(f.path, f.path+suffix); err != nil {
+		f.t.Fatal(err)
+	}
+	f.write(newContent)
+}
+
+// copyTruncate 模拟 logrotate copytruncate：复制后原地截断，再写入新内容
+func (f *logFile) copyTruncate(suffix, newContent string) {
+	f.t.Helper()
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	if err := os.WriteFile(f.path+suffix, data, 0644); err != nil {
+		f.t.Fatal(err)
+	}
+	if err := os.Truncate(f.path, 0); err != nil {
+		f.t.Fatal(err)
+	}
+	f.append(newContent)
+}
+
+// compress 模拟 delaycompress 之后的压缩：path+suffix 压缩为 .gz 并删除原文件
+func (f *logFile) compress(suffix string) {
+	f.t.Helper()
+	data, err := os.ReadFile(f.path + suffix)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	out, err := os.Create(f.path + suffix + ".gz")
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	gz := gzip.NewWriter(out)
+	gz.Write(data)
+	gz.Close()
+	out.Close()
+	os.Remove(f.path + suffix)
+}
+
+// readAll 读取一次新增的行
+func readAll(t *testing.T, tail *Tailer) []string {
+	t.Helper()
+	var lines []string
+	if err := tail.ReadLines(func(line string) { lines = append(lines, line) }); err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+func expectLines(t *testing.T, step string, got []string, want ...string) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%s: got %q, want %q", step, got, want)
+	}
+}
+
+func TestTailerAppend(t *testing.T) {
+	log := newLogFile(t)
+	log.write("old 1\nold 2\n")
+
+	// 没有历史位置时默认从末尾开始
+	tail := New(log.path, Options{})
+	defer tail.Close()
+	expectLines(t, "first read", readAll(t, tail))
+
+	log.append("new 1\nnew ")
+	expectLines(t, "append", readAll(t, tail), "new 1")
+
+	// 不完整的行等到换行后输出
+	log.append("2\n")
+	expectLines(t, "complete line", readAll(t, tail), "new 2")
+}
+
+func TestTailerFromStart(t *testing.T) {
+	log := newLogFile(t)
+	log.write("line 1\nline 2\n")
+
+	tail := New(log.path, Options{FromStart: true})
+	defer tail.Close()
+	expectLines(t, "first read", readAll(t, tail), "line 1", "line 2")
+}
+
+func TestTailerRenameRotation(t *testing.T) {
+	log := newLogFile(t)
+	log.write("a 1\n")
+	tail := New(log.path, Options{FromStart: true})
+	defer tail.Close()
+	expectLines(t, "first read", readAll(t, tail), "a 1")
+
+	// 轮转前最后写入的内容仍从旧句柄读取
+	log.append("a 2\n")
+	log.rename(".1", "b 1\n")
+	expectLines(t, "after rotation", readAll(t, tail), "a 2", "b 1")
+
+	log.append("b 2\n")
+	expectLines(t, "new file", readAll(t, tail), "b 2")
+}
+
+func TestTailerCopyTruncate(t *testing.T) {
+	log := newLogFile(t)
+	log.write("a 1\n")
+	tail := New(log.path, Options{FromStart: true})
+	defer tail.Close()
+	expectLines(t, "first read", readAll(t, tail), "a 1")
+
+	// 截断前最后写入的内容从副本中读取
+	log.append("a 2\n")
+	log.copyTruncate(".1", "b 1\n")
+	expectLines(t, "after truncate", readAll(t, tail), "a 2", "b 1")
+
+	// 截断后写入的内容超过原偏移，靠指纹识别
+	log.append("b 2 with a much longer line\n")
+	log.copyTruncate(".1", "c 1 with a line longer than everything before it\n")
+	expectLines(t, "truncate then regrow", readAll(t, tail), "b 2 with a much longer line", "c 1 with a line longer than everything before it")
+}
+
+// restart 关闭旧的 Tailer 并用同一状态文件创建新的
+func restart(tail *Tailer, path, stateFile string) *Tailer {
+	tail.Close()
+	return New(path, Options{StateFile: stateFile})
+}
+
+func TestTailerRestart(t *testing.T) {
+	tests := []struct {
+		name   string
+		rotate func(log *logFile)
+	}{
+		{
+			name:   "no rotation",
+			rotate: func(log *logFile) { log.append("b 1\n") },
+		},
+		{
+			name:   "rename",
+			rotate: func(log *logFile) { log.rename(".1", "b 1\n") },
+		},
+		{
+			name:   "rename dated",
+			rotate: func(log *logFile) { log.rename("-20261019", "b 1\n") },
+		},
+		{
+			name: "rename compressed",
+			rotate: func(log *logFile) {
+				log.rename(".1", "b 1\n")
+				log.compress(".1")
+			},
+		},
+		{
+			name:   "copytruncate",
+			rotate: func(log *logFile) { log.copyTruncate(".1", "b 1\n") },
+		},
+		{
+			name: "copytruncate regrown",
+			rotate: func(log *logFile) {
+				log.copyTruncate(".1", "b 1 with a line longer than everything written before\n")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := newLogFile(t)
+			stateFile := StateFileFor(t.TempDir(), log.path)
+			log.write("a 1\n")
+
+			tail := New(log.path, Options{StateFile: stateFile, FromStart: true})
+			expectLines(t, "first read", readAll(t, tail), "a 1")
+
+			// 停止期间写入并轮转
+			tail.Close()
+			log.append("a 2\n")
+			tt.rotate(log)
+
+			tail = restart(tail, log.path, stateFile)
+			defer tail.Close()
+			got := readAll(t, tail)
+			want := []string{"a 2"}
+			if tt.name == "no rotation" {
+				want = append(want, "b 1")
+			} else {
+				want = append(want, readLinesOf(t, log.path)...)
+			}
+			expectLines(t, "after restart", got, want...)
+		})
+	}
+}
+
+// readLinesOf 读取当前日志文件的全部行
+func readLinesOf(t *testing.T, path string) []string {
+	t.Helper()
+	tail := New(path, Options{FromStart: true})
+	defer tail.Close()
+	return readAll(t, tail)
+}
+
+func TestTailerPersistError(t *testing.T) {
+	log := newLogFile(t)
+	log.write("a 1\n")
+
+	// 状态目录是普通文件，无法创建
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	tail := New(log.path, Options{StateFile: filepath.Join(blocker, "state.json"), FromStart: true})
+	defer tail.Close()
+
+	var lines []string
+	err := tail.ReadLines(func(line string) { lines = append(lines, line) })
+	if _, ok := err.(*PersistError); !ok {
+		t.Fatalf("err = %v, want *PersistError", err)
+	}
+	expectLines(t, "read despite persist error", lines, "a 1")
+
+	// 文件句柄保持打开，继续读取新内容
+	log.append("a 2\n")
+	lines = nil
+	tail.ReadLines(func(line string) { lines = append(lines, line) })
+	expectLines(t, "second read", lines, "a 2")
+}
+
+func TestWindowCounter(t *testing.T) {
+	w := NewWindowCounter(5 * time.Minute)
+	now := time.Now()
+
+	w.AddAt(now.Add(-time.Minute), "a", 2)
+	w.AddAt(now, "a", 1)
+	w.AddAt(now.Add(-2*time.Minute), "b", 1) // 乱序到达
+	// 补读的积压日志早于窗口，不计入
+	w.AddAt(now.Add(-time.Hour), "a", 100)
+	// 时间无法解析时按当前时刻计
+	w.AddAt(time.Time{}, "c", 1)
+
+	if got := w.Count(); got != 5 {
+		t.Errorf("Count = %d, want 5", got)
+	}
+	if got := w.CountByKey(); !reflect.DeepEqual(got, map[string]uint64{"a": 3, "b": 1, "c": 1}) {
+		t.Errorf("CountByKey = %v", got)
+	}
+	for i := 1; i < len(w.buckets); i++ {
+		if !w.buckets[i-1].at.Before(w.buckets[i].at) {
+			t.Fatalf("buckets not sorted: %v", w.buckets)
+		}
+	}
+
+	w.Remove("a")
+	if got := w.Count(); got != 2 {
+		t.Errorf("Count after Remove = %d, want 2", got)
+	}
+}
@@ -0,0 +1,100 @@
+package tailer
+
+import (
+	"sort"
+	"time"
+)
+
+// WindowCounter 滑动时间窗口内按键计数，按秒聚合以限制内存
+type WindowCounter struct {
+	window  time.Duration
+	buckets []windowBucket
+}
+
+// windowBucket 一秒内的计数
+type windowBucket struct {
+	at     time.Time
+	counts map[string]uint64
+}
+
+// NewWindowCounter 创建指定窗口长度的计数器
+func NewWindowCounter(window time.Duration) *WindowCounter {
+	return &WindowCounter{window: window}
+}
+
+// Window 返回窗口长度
+func (w *WindowCounter) Window() time.Duration {
+	return w.window
+}
+
+// Add 在当前时刻为 key 增加 n
+func (w *WindowCounter) Add(key string, n uint64) {
+	w.AddAt(time.Now(), key, n)
+}
+
+// AddAt 按事件发生时刻为 key 增加 n
+// 重启或轮转后补读的积压日志落在各自的时刻，窗口之外的直接丢弃；
+// at 为零值（时间无法解析）或晚于当前时刻（时钟偏差）时按当前时刻计
+func (w *WindowCounter) AddAt(at time.Time, key string, n uint64) {
+	now := time.Now()
+	if at.IsZero() || at.After(now) {
+		at = now
+	}
+	if at.Before(now.Add(-w.window)) {
+		return
+	}
+	at = at.Truncate(time.Second)
+
+	// 桶按时间有序，二分查找插入位置
+	i := sort.Search(len(w.buckets), func(i int) bool { return !w.buckets[i].at.Before(at) })
+	if i == len(w.buckets) || !w.buckets[i].at.Equal(at) {
+		w.buckets = append(w.buckets, windowBucket{})
+		copy(w.buckets[i+1:], w.buckets[i:])
+		w.buckets[i] = windowBucket{at: at, counts: map[string]uint64{}}
+	}
+	w.buckets[i].counts[key] += n
+	w.prune()
+}
+
+// Count 返回窗口内所有键的计数之和
+func (w *WindowCounter) Count() uint64 {
+	w.prune()
+	var total uint64
+	for _, bucket := range w.buckets {
+		for _, n := range bucket.counts {
+			total += n
+		}
+	}
+	return total
+}
+
+// CountByKey 返回窗口内按键汇总的计数
+func (w *WindowCounter) CountByKey() map[string]uint64 {
+	w.prune()
+	result := make(map[string]uint64)
+	for _, bucket := range w.buckets {
+		for key, n := range bucket.counts {
+			result[key] += n
+		}
+	}
+	return result
+}
+
+// Remove 删除窗口内 key 的全部计数
+func (w *WindowCounter) Remove(key string) {
+	for _, bucket := range w.buckets {
+		delete(bucket.counts, key)
+	}
+}
+
+// prune 丢弃窗口之外的桶
+func (w *WindowCounter) prune() {
+	cutoff := time.Now().Add(-w.window)
+	i := 0
+	for i < len(w.buckets) && w.buckets[i].at.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		w.buckets = append(w.buckets[:0], w.buckets[i:]...)
+	}
+}